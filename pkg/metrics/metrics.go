@@ -0,0 +1,47 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics defines the sink that the simulated kubelet reports
+// runtime observations to.
+package metrics
+
+import "time"
+
+// NodeMetric is a point-in-time sample of a node's allocated resources as
+// seen by the simulated kubelet.
+type NodeMetric struct {
+	MetricType string
+	SampleTime time.Time
+	Capacity   map[string]string
+}
+
+// Interface is implemented by metrics sinks that RemoteRuntimeService
+// reports runtime observations to.
+type Interface interface {
+	// LogNodeMetrics records a snapshot of the node's allocated resources.
+	LogNodeMetrics(nm *NodeMetric)
+	// LogContainerVanished records that a container referenced by the
+	// simulated kubelet could no longer be found in the runtime, most often
+	// because it raced with removal between a list and a per-container call.
+	LogContainerVanished(containerID string)
+	// LogCRICall records the latency and outcome of a single CRI method
+	// call, keyed by its method name (e.g. "RunPodSandbox").
+	LogCRICall(method string, duration time.Duration, err error)
+	// LogPodLifecycle records a pod sandbox lifecycle event (e.g.
+	// "PodTerminated") at the given timestamp, along with the simulated
+	// exit code and phase that produced it.
+	LogPodLifecycle(event string, sandboxID, namespace, name string, exitCode int32, phase string, ts time.Time)
+}
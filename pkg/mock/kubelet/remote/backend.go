@@ -0,0 +1,174 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	runtimeapiv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+
+	"volcano.sh/kubesim/pkg/remote/types"
+)
+
+// RuntimeBackend answers the CRI verbs that a simulation doesn't need a live
+// containerd/cri-o socket to serve: ExecSync, Exec, Attach, PortForward,
+// ContainerStats and Status. RemoteRuntimeService dispatches through
+// whichever backend it was constructed with, grpcBackend or fakeBackend.
+type RuntimeBackend interface {
+	ExecSync(ctx context.Context, containerID string, cmd []string, timeout time.Duration) (stdout, stderr []byte, exitCode int32, err error)
+	Exec(ctx context.Context, req *runtimeapi.ExecRequest) (*runtimeapi.ExecResponse, error)
+	Attach(ctx context.Context, req *runtimeapi.AttachRequest) (*runtimeapi.AttachResponse, error)
+	PortForward(ctx context.Context, req *runtimeapi.PortForwardRequest) (*runtimeapi.PortForwardResponse, error)
+	ContainerStats(ctx context.Context, containerID string) (*runtimeapi.ContainerStats, error)
+	Status(ctx context.Context) (*runtimeapi.RuntimeStatus, error)
+}
+
+// grpcBackend answers RuntimeBackend by proxying to the real CRI runtime.
+// ContainerStats and Status use the client for the negotiated API version;
+// ExecSync, Exec, Attach and PortForward always use the v1alpha2 client,
+// whichever version was negotiated.
+type grpcBackend struct {
+	apiVersion      string
+	runtimeClient   runtimeapi.RuntimeServiceClient
+	runtimeClientV1 runtimeapiv1.RuntimeServiceClient
+}
+
+func (b *grpcBackend) ExecSync(ctx context.Context, containerID string, cmd []string, timeout time.Duration) ([]byte, []byte, int32, error) {
+	resp, err := b.runtimeClient.ExecSync(ctx, &runtimeapi.ExecSyncRequest{
+		ContainerId: containerID,
+		Cmd:         cmd,
+		Timeout:     int64(timeout.Seconds()),
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return resp.Stdout, resp.Stderr, resp.ExitCode, nil
+}
+
+func (b *grpcBackend) Exec(ctx context.Context, req *runtimeapi.ExecRequest) (*runtimeapi.ExecResponse, error) {
+	return b.runtimeClient.Exec(ctx, req)
+}
+
+func (b *grpcBackend) Attach(ctx context.Context, req *runtimeapi.AttachRequest) (*runtimeapi.AttachResponse, error) {
+	return b.runtimeClient.Attach(ctx, req)
+}
+
+func (b *grpcBackend) PortForward(ctx context.Context, req *runtimeapi.PortForwardRequest) (*runtimeapi.PortForwardResponse, error) {
+	return b.runtimeClient.PortForward(ctx, req)
+}
+
+func (b *grpcBackend) ContainerStats(ctx context.Context, containerID string) (*runtimeapi.ContainerStats, error) {
+	if b.apiVersion == CRIVersionV1 {
+		resp, err := b.runtimeClientV1.ContainerStats(ctx, &runtimeapiv1.ContainerStatsRequest{ContainerId: containerID})
+		if err != nil {
+			return nil, err
+		}
+		return types.ToV1alpha2ContainerStats(types.FromV1ContainerStats(resp.GetStats())), nil
+	}
+	resp, err := b.runtimeClient.ContainerStats(ctx, &runtimeapi.ContainerStatsRequest{ContainerId: containerID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetStats(), nil
+}
+
+func (b *grpcBackend) Status(ctx context.Context) (*runtimeapi.RuntimeStatus, error) {
+	if b.apiVersion == CRIVersionV1 {
+		resp, err := b.runtimeClientV1.Status(ctx, &runtimeapiv1.StatusRequest{})
+		if err != nil {
+			return nil, err
+		}
+		return types.ToV1alpha2RuntimeStatus(types.FromV1RuntimeStatus(resp.Status)), nil
+	}
+	resp, err := b.runtimeClient.Status(ctx, &runtimeapi.StatusRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Status, nil
+}
+
+// fakeBackend answers RuntimeBackend with deterministic, synthetic responses
+// so a simulation can run without a live containerd/cri-o socket: ExecSync
+// always succeeds with empty output, Exec/Attach/PortForward return a fake
+// streaming URL, ContainerStats is derived from the requested container's
+// owning sandbox's resource request (via podSandBoxCache's container-to-
+// sandbox tracking) scaled by a configurable usage fraction, and Status is
+// always Ready.
+type fakeBackend struct {
+	cache       *podSandBoxCache
+	cpuFraction float64 // fraction of the sandbox's requested CPU reported as "in use"
+	memFraction float64 // fraction of the sandbox's requested memory reported as "in use"
+}
+
+// newFakeBackend returns a fakeBackend reporting 50% utilization of each
+// sandbox's requested CPU and memory.
+func newFakeBackend(cache *podSandBoxCache) *fakeBackend {
+	return &fakeBackend{cache: cache, cpuFraction: 0.5, memFraction: 0.5}
+}
+
+func (b *fakeBackend) ExecSync(ctx context.Context, containerID string, cmd []string, timeout time.Duration) ([]byte, []byte, int32, error) {
+	return []byte{}, []byte{}, 0, nil
+}
+
+func (b *fakeBackend) Exec(ctx context.Context, req *runtimeapi.ExecRequest) (*runtimeapi.ExecResponse, error) {
+	return &runtimeapi.ExecResponse{Url: fmt.Sprintf("fake://exec/%s", req.ContainerId)}, nil
+}
+
+func (b *fakeBackend) Attach(ctx context.Context, req *runtimeapi.AttachRequest) (*runtimeapi.AttachResponse, error) {
+	return &runtimeapi.AttachResponse{Url: fmt.Sprintf("fake://attach/%s", req.ContainerId)}, nil
+}
+
+func (b *fakeBackend) PortForward(ctx context.Context, req *runtimeapi.PortForwardRequest) (*runtimeapi.PortForwardResponse, error) {
+	return &runtimeapi.PortForwardResponse{Url: fmt.Sprintf("fake://portforward/%s", req.PodSandboxId)}, nil
+}
+
+func (b *fakeBackend) ContainerStats(ctx context.Context, containerID string) (*runtimeapi.ContainerStats, error) {
+	now := time.Now().UnixNano()
+	stats := &runtimeapi.ContainerStats{
+		Attributes: &runtimeapi.ContainerAttributes{Id: containerID},
+		Cpu:        &runtimeapi.CpuUsage{Timestamp: now, UsageCoreNanoSeconds: &runtimeapi.UInt64Value{}},
+		Memory:     &runtimeapi.MemoryUsage{Timestamp: now, WorkingSetBytes: &runtimeapi.UInt64Value{}},
+	}
+	sandboxID, ok := b.cache.containerSandbox(containerID)
+	if !ok {
+		return stats, nil
+	}
+	info, ok := b.cache.snapshot()[sandboxID]
+	if !ok {
+		return stats, nil
+	}
+	if cpu, ok := info.Request[v1.ResourceCPU]; ok {
+		stats.Cpu.UsageCoreNanoSeconds.Value = uint64(float64(cpu.MilliValue()) * b.cpuFraction * 1e6)
+	}
+	if mem, ok := info.Request[v1.ResourceMemory]; ok {
+		stats.Memory.WorkingSetBytes.Value = uint64(float64(mem.Value()) * b.memFraction)
+	}
+	return stats, nil
+}
+
+func (b *fakeBackend) Status(ctx context.Context) (*runtimeapi.RuntimeStatus, error) {
+	return &runtimeapi.RuntimeStatus{
+		Conditions: []*runtimeapi.RuntimeCondition{
+			{Type: runtimeapi.RuntimeReady, Status: true},
+			{Type: runtimeapi.NetworkReady, Status: true},
+		},
+	}, nil
+}
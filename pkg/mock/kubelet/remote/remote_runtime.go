@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,68 +28,293 @@ import (
 	"k8s.io/klog/v2"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	clientset "k8s.io/client-go/kubernetes"
 	internalapi "k8s.io/cri-api/pkg/apis"
+	runtimeapiv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
 	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
 	"k8s.io/kubernetes/pkg/kubelet/util"
 	"k8s.io/kubernetes/pkg/kubelet/util/logreduction"
 	utilexec "k8s.io/utils/exec"
 
 	"volcano.sh/kubesim/pkg/metrics"
+	"volcano.sh/kubesim/pkg/remote/checkpoint"
+	criErrors "volcano.sh/kubesim/pkg/remote/errors"
+	"volcano.sh/kubesim/pkg/remote/types"
 )
 
+// CRI API versions that RemoteRuntimeService can negotiate with a runtime.
+const (
+	CRIVersionV1         = "v1"
+	CRIVersionV1alpha2   = "v1alpha2"
+	CRIVersionAutoDetect = ""
+)
+
+// checkpointSyncInterval is how often the pod sandbox cache is fully
+// re-flushed to disk, on top of the per-mutation checkpoints taken as
+// sandboxes are added and removed.
+const checkpointSyncInterval = 30 * time.Second
+
 // RemoteRuntimeService is a gRPC implementation of internalapi.RuntimeService.
 type RemoteRuntimeService struct {
-	timeout       time.Duration
-	runtimeClient runtimeapi.RuntimeServiceClient
+	timeout         time.Duration
+	runtimeClient   runtimeapi.RuntimeServiceClient
+	runtimeClientV1 runtimeapiv1.RuntimeServiceClient
+	// apiVersion is the CRI API version negotiated with the runtime at
+	// connect time (one of CRIVersionV1 or CRIVersionV1alpha2).
+	apiVersion string
 	// Cache last per-container error message to reduce log spam
 	logReduction *logreduction.LogReduction
 	cache        *podSandBoxCache
-	client       *clientset.Clientset
+	client       clientset.Interface
 	sink         metrics.Interface
+	checkpointer *checkpoint.Checkpointer
+	// backend answers ExecSync, Exec, Attach, PortForward, ContainerStats and
+	// Status, either by proxying to the real runtime or synthetically.
+	backend RuntimeBackend
 }
 
+// fakeEndpointPrefix marks an endpoint as backed by the in-process
+// fakeBackend instead of a real CRI socket, e.g. "fake://simulated".
+const fakeEndpointPrefix = "fake://"
+
 const (
 	// How frequently to report identical errors
 	identicalErrorDelay = 1 * time.Minute
 )
 
-// NewRemoteRuntimeService creates a new internalapi.RuntimeService.
-func NewRemoteRuntimeService(endpoint string, connectionTimeout time.Duration, client *clientset.Clientset, sink metrics.Interface) (internalapi.RuntimeService, error) {
-	klog.V(3).Infof("Connecting to runtime service %s", endpoint)
-	addr, dialer, err := util.GetAddressAndDialer(endpoint)
-	if err != nil {
-		return nil, err
+// NewRemoteRuntimeService creates a new internalapi.RuntimeService. criVersion
+// pins the CRI API version to negotiate with the runtime (CRIVersionV1 or
+// CRIVersionV1alpha2); pass CRIVersionAutoDetect (typically sourced from a
+// --cri-version flag) to probe the runtime and prefer v1, falling back to
+// v1alpha2 when the runtime doesn't implement it. checkpointDir, if set,
+// persists the pod sandbox cache so it survives a restart; pass "" to
+// disable checkpointing.
+func NewRemoteRuntimeService(endpoint string, connectionTimeout time.Duration, client clientset.Interface, sink metrics.Interface, criVersion string, checkpointDir string) (internalapi.RuntimeService, error) {
+	pc := &podSandBoxCache{
+		PodSandBox: make(map[string]*podSandBoxInfo),
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout)
-	defer cancel()
 
-	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithContextDialer(dialer), grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize)))
+	var service *RemoteRuntimeService
+	if strings.HasPrefix(endpoint, fakeEndpointPrefix) {
+		klog.V(3).Infof("Using fake runtime backend for %s, no CRI socket required", endpoint)
+		fakeAPIVersion, err := resolveFakeAPIVersion(criVersion)
+		if err != nil {
+			return nil, err
+		}
+		checkpointer := checkpoint.NewCheckpointer(checkpointDir)
+		rehydrated, err := checkpointer.LoadAll()
+		if err != nil {
+			klog.Errorf("Failed to load pod sandbox checkpoints from %s: %v", checkpointDir, err)
+		}
+		for id, rec := range rehydrated {
+			pc.PodSandBox[id] = &podSandBoxInfo{
+				Config:         rec.Config,
+				StartAt:        rec.StartAt,
+				Request:        rec.Request,
+				PodDuration:    rec.PodDuration,
+				PodTermination: rec.PodTermination,
+				ExitCode:       rec.ExitCode,
+			}
+		}
+
+		service = &RemoteRuntimeService{
+			timeout:      connectionTimeout,
+			apiVersion:   fakeAPIVersion,
+			logReduction: logreduction.NewLogReduction(identicalErrorDelay),
+			cache:        pc,
+			client:       client,
+			sink:         sink,
+			checkpointer: checkpointer,
+		}
+		service.backend = newFakeBackend(pc)
+	} else {
+		klog.V(3).Infof("Connecting to runtime service %s", endpoint)
+		addr, dialer, err := util.GetAddressAndDialer(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout)
+		defer cancel()
+
+		conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithContextDialer(dialer), grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize)))
+		if err != nil {
+			klog.Errorf("Connect remote runtime %s failed: %v", addr, err)
+			return nil, err
+		}
+
+		clientV1alpha2 := runtimeapi.NewRuntimeServiceClient(conn)
+		clientV1 := runtimeapiv1.NewRuntimeServiceClient(conn)
+		negotiated, err := negotiateAPIVersion(ctx, criVersion, clientV1, clientV1alpha2)
+		if err != nil {
+			return nil, err
+		}
+		klog.V(2).Infof("Negotiated CRI API version %s with runtime %s", negotiated, addr)
+
+		checkpointer := checkpoint.NewCheckpointer(checkpointDir)
+		// Reconciliation gets its own fresh deadline rather than reusing ctx,
+		// which was sized only for "establish the connection" and may already
+		// be mostly spent by the dial and version negotiation above; without
+		// this, a slow/loaded startup can make ListPodSandbox time out before
+		// reconciliation runs, silently discarding every checkpoint.
+		reconcileCtx, reconcileCancel := context.WithTimeout(context.Background(), connectionTimeout)
+		defer reconcileCancel()
+		rehydrated, err := loadAndReconcileCheckpoints(reconcileCtx, checkpointer, negotiated, clientV1, clientV1alpha2)
+		if err != nil {
+			klog.Errorf("Failed to load pod sandbox checkpoints from %s: %v", checkpointDir, err)
+		}
+		for id, info := range rehydrated {
+			pc.PodSandBox[id] = info
+		}
+
+		service = &RemoteRuntimeService{
+			timeout:         connectionTimeout,
+			runtimeClient:   clientV1alpha2,
+			runtimeClientV1: clientV1,
+			apiVersion:      negotiated,
+			logReduction:    logreduction.NewLogReduction(identicalErrorDelay),
+			cache:           pc,
+			client:          client,
+			sink:            sink,
+			checkpointer:    checkpointer,
+		}
+		service.backend = &grpcBackend{
+			apiVersion:      negotiated,
+			runtimeClient:   clientV1alpha2,
+			runtimeClientV1: clientV1,
+		}
+	}
+
+	go wait.Until(service.podHouseKeeping, time.Second, context.TODO().Done())
+	go wait.Until(service.allocatedResourcesHouseKeeping, 15*time.Second, context.TODO().Done())
+	go wait.Until(func() {
+		if err := service.Sync(); err != nil {
+			klog.Errorf("Failed to checkpoint pod sandbox cache: %v", err)
+		}
+	}, checkpointSyncInterval, context.TODO().Done())
+
+	return service, nil
+}
+
+// loadAndReconcileCheckpoints loads persisted pod sandbox records and drops
+// any whose sandbox no longer exists in the runtime, so a stale checkpoint
+// from a sandbox that was removed while the simulator was down doesn't come
+// back to life.
+func loadAndReconcileCheckpoints(ctx context.Context, checkpointer *checkpoint.Checkpointer, apiVersion string, clientV1 runtimeapiv1.RuntimeServiceClient, clientV1alpha2 runtimeapi.RuntimeServiceClient) (map[string]*podSandBoxInfo, error) {
+	records, err := checkpointer.LoadAll()
 	if err != nil {
-		klog.Errorf("Connect remote runtime %s failed: %v", addr, err)
 		return nil, err
 	}
+	if len(records) == 0 {
+		return nil, nil
+	}
 
-	pc := &podSandBoxCache{
-		PodSandBox: make(map[string]*podSandBoxInfo),
+	live := make(map[string]bool)
+	if apiVersion == CRIVersionV1 {
+		resp, err := clientV1.ListPodSandbox(ctx, &runtimeapiv1.ListPodSandboxRequest{})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range resp.Items {
+			live[s.Id] = true
+		}
+	} else {
+		resp, err := clientV1alpha2.ListPodSandbox(ctx, &runtimeapi.ListPodSandboxRequest{})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range resp.Items {
+			live[s.Id] = true
+		}
+	}
+
+	rehydrated := make(map[string]*podSandBoxInfo, len(records))
+	for id, rec := range records {
+		if !live[id] {
+			if err := checkpointer.Delete(id); err != nil {
+				klog.Errorf("Failed to drop stale checkpoint for pod sandbox %s: %v", id, err)
+			}
+			continue
+		}
+		rehydrated[id] = &podSandBoxInfo{
+			Config:         rec.Config,
+			StartAt:        rec.StartAt,
+			Request:        rec.Request,
+			PodDuration:    rec.PodDuration,
+			PodTermination: rec.PodTermination,
+			ExitCode:       rec.ExitCode,
+		}
 	}
+	return rehydrated, nil
+}
 
-	service := &RemoteRuntimeService{
-		timeout:       connectionTimeout,
-		runtimeClient: runtimeapi.NewRuntimeServiceClient(conn),
-		logReduction:  logreduction.NewLogReduction(identicalErrorDelay),
-		cache:         pc,
-		client:        client,
-		sink:          sink,
+// Sync forces an immediate full checkpoint of the pod sandbox cache, for use
+// by tests and shutdown hooks that can't wait for the periodic sync.
+func (r *RemoteRuntimeService) Sync() error {
+	if r.checkpointer == nil {
+		return nil
+	}
+	snapshot := r.cache.snapshot()
+	records := make(map[string]*checkpoint.Record, len(snapshot))
+	for id, info := range snapshot {
+		records[id] = &checkpoint.Record{
+			SandboxID:      id,
+			Config:         info.Config,
+			StartAt:        info.StartAt,
+			Request:        info.Request,
+			PodDuration:    info.PodDuration,
+			PodTermination: info.PodTermination,
+			ExitCode:       info.ExitCode,
+		}
 	}
+	return r.checkpointer.Sync(records)
+}
 
-	go wait.Until(service.podHouseKeeping, time.Second, context.TODO().Done())
-	go wait.Until(service.allocatedResourcesHouseKeeping, 15*time.Second, context.TODO().Done())
+// negotiateAPIVersion determines which CRI API version to speak to the
+// runtime. When pinned is CRIVersionV1 or CRIVersionV1alpha2 it is used
+// as-is without probing, and any other non-empty value is rejected with an
+// error; when pinned is CRIVersionAutoDetect, v1 is preferred and v1alpha2 is used as a fallback for older runtimes (e.g.
+// containerd < 1.6, cri-o < 1.20) that don't implement the v1 service.
+func negotiateAPIVersion(ctx context.Context, pinned string, clientV1 runtimeapiv1.RuntimeServiceClient, clientV1alpha2 runtimeapi.RuntimeServiceClient) (string, error) {
+	switch pinned {
+	case CRIVersionV1, CRIVersionV1alpha2:
+		return pinned, nil
+	case CRIVersionAutoDetect:
+		if _, err := clientV1.Version(ctx, &runtimeapiv1.VersionRequest{}); err == nil {
+			return CRIVersionV1, nil
+		}
+		if _, err := clientV1alpha2.Version(ctx, &runtimeapi.VersionRequest{}); err == nil {
+			return CRIVersionV1alpha2, nil
+		}
+		return "", fmt.Errorf("failed to negotiate CRI API version: runtime implements neither %s nor %s", CRIVersionV1, CRIVersionV1alpha2)
+	default:
+		return "", fmt.Errorf("unsupported CRI API version %q, must be one of %q, %q or %q", pinned, CRIVersionV1, CRIVersionV1alpha2, CRIVersionAutoDetect)
+	}
+}
 
-	return service, nil
+// resolveFakeAPIVersion validates criVersion for a fake:// endpoint the same
+// way negotiateAPIVersion does for a real one. There's no runtime to probe,
+// so CRIVersionAutoDetect resolves to CRIVersionV1alpha2, the version
+// fakeBackend's synthetic responses are modeled on; an explicit CRIVersionV1
+// or CRIVersionV1alpha2 is honored as-is so APIVersion() reports what was
+// actually requested instead of silently ignoring it.
+func resolveFakeAPIVersion(criVersion string) (string, error) {
+	switch criVersion {
+	case CRIVersionV1, CRIVersionV1alpha2:
+		return criVersion, nil
+	case CRIVersionAutoDetect:
+		return CRIVersionV1alpha2, nil
+	default:
+		return "", fmt.Errorf("unsupported CRI API version %q, must be one of %q, %q or %q", criVersion, CRIVersionV1, CRIVersionV1alpha2, CRIVersionAutoDetect)
+	}
+}
+
+// APIVersion returns the CRI API version negotiated with the runtime.
+func (r *RemoteRuntimeService) APIVersion() string {
+	return r.apiVersion
 }
 
 // Version returns the runtime name, runtime version and runtime API version.
@@ -96,19 +322,43 @@ func (r *RemoteRuntimeService) Version(apiVersion string) (*runtimeapi.VersionRe
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	typedVersion, err := r.runtimeClient.Version(ctx, &runtimeapi.VersionRequest{
-		Version: apiVersion,
-	})
-	if err != nil {
-		klog.Errorf("Version from runtime service failed: %v", err)
-		return nil, err
+	var internalVersion *types.VersionResponse
+	if r.apiVersion == CRIVersionV1 {
+		var typedVersion *runtimeapiv1.VersionResponse
+		err := r.instrumented("Version", func() error {
+			var err error
+			typedVersion, err = r.runtimeClientV1.Version(ctx, &runtimeapiv1.VersionRequest{Version: apiVersion})
+			return err
+		})
+		if err != nil {
+			klog.Errorf("Version from runtime service failed: %v", err)
+			return nil, err
+		}
+		internalVersion = types.FromV1VersionResponse(typedVersion)
+	} else {
+		var typedVersion *runtimeapi.VersionResponse
+		err := r.instrumented("Version", func() error {
+			var err error
+			typedVersion, err = r.runtimeClient.Version(ctx, &runtimeapi.VersionRequest{Version: apiVersion})
+			return err
+		})
+		if err != nil {
+			klog.Errorf("Version from runtime service failed: %v", err)
+			return nil, err
+		}
+		internalVersion = types.FromV1alpha2VersionResponse(typedVersion)
 	}
 
-	if typedVersion.Version == "" || typedVersion.RuntimeName == "" || typedVersion.RuntimeApiVersion == "" || typedVersion.RuntimeVersion == "" {
-		return nil, fmt.Errorf("not all fields are set in VersionResponse (%q)", *typedVersion)
+	if internalVersion.Version == "" || internalVersion.RuntimeName == "" || internalVersion.RuntimeAPIVersion == "" || internalVersion.RuntimeVersion == "" {
+		return nil, fmt.Errorf("not all fields are set in VersionResponse (%+v)", *internalVersion)
 	}
 
-	return typedVersion, err
+	return &runtimeapi.VersionResponse{
+		Version:           internalVersion.Version,
+		RuntimeName:       internalVersion.RuntimeName,
+		RuntimeVersion:    internalVersion.RuntimeVersion,
+		RuntimeApiVersion: internalVersion.RuntimeAPIVersion,
+	}, nil
 }
 
 // RunPodSandbox creates and starts a pod-level sandbox. Runtimes should ensure
@@ -119,23 +369,54 @@ func (r *RemoteRuntimeService) RunPodSandbox(config *runtimeapi.PodSandboxConfig
 	ctx, cancel := getContextWithTimeout(r.timeout * 2)
 	defer cancel()
 
-	resp, err := r.runtimeClient.RunPodSandbox(ctx, &runtimeapi.RunPodSandboxRequest{
-		Config:         config,
-		RuntimeHandler: runtimeHandler,
-	})
-	if err != nil {
-		klog.Errorf("RunPodSandbox from runtime service failed: %v", err)
-		return "", err
+	var podSandboxID string
+	if r.apiVersion == CRIVersionV1 {
+		v1Config, err := types.ConvertPodSandboxConfigToV1(config)
+		if err != nil {
+			klog.Errorf("Failed to convert PodSandboxConfig to v1: %v", err)
+			return "", err
+		}
+		var resp *runtimeapiv1.RunPodSandboxResponse
+		err = r.instrumented("RunPodSandbox", func() error {
+			var err error
+			resp, err = r.runtimeClientV1.RunPodSandbox(ctx, &runtimeapiv1.RunPodSandboxRequest{
+				Config:         v1Config,
+				RuntimeHandler: runtimeHandler,
+			})
+			return err
+		})
+		if err != nil {
+			klog.Errorf("RunPodSandbox from runtime service failed: %v", err)
+			return "", err
+		}
+		podSandboxID = resp.PodSandboxId
+	} else {
+		var resp *runtimeapi.RunPodSandboxResponse
+		err := r.instrumented("RunPodSandbox", func() error {
+			var err error
+			resp, err = r.runtimeClient.RunPodSandbox(ctx, &runtimeapi.RunPodSandboxRequest{
+				Config:         config,
+				RuntimeHandler: runtimeHandler,
+			})
+			return err
+		})
+		if err != nil {
+			klog.Errorf("RunPodSandbox from runtime service failed: %v", err)
+			return "", err
+		}
+		podSandboxID = resp.PodSandboxId
 	}
 
-	if resp.PodSandboxId == "" {
+	if podSandboxID == "" {
 		errorMessage := fmt.Sprintf("PodSandboxId is not set for sandbox %q", config.GetMetadata())
 		klog.Errorf("RunPodSandbox failed: %s", errorMessage)
 		return "", errors.New(errorMessage)
 	}
 
-	r.cache.addPodSandBox(resp.PodSandboxId, config, r.getPodRequest(config.Metadata.Name, config.Metadata.Namespace))
-	return resp.PodSandboxId, nil
+	request, simSpec := r.getPodRequestAndSimSpec(config.Metadata.Name, config.Metadata.Namespace)
+	r.cache.addPodSandBox(podSandboxID, config, request, simSpec.Duration, simSpec.Termination, simSpec.ExitCode)
+	r.persistSandbox(podSandboxID)
+	return podSandboxID, nil
 }
 
 // StopPodSandbox stops the sandbox. If there are any running containers in the
@@ -144,8 +425,18 @@ func (r *RemoteRuntimeService) StopPodSandbox(podSandBoxID string) error {
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	_, err := r.runtimeClient.StopPodSandbox(ctx, &runtimeapi.StopPodSandboxRequest{
-		PodSandboxId: podSandBoxID,
+	err := r.instrumented("StopPodSandbox", func() error {
+		var err error
+		if r.apiVersion == CRIVersionV1 {
+			_, err = r.runtimeClientV1.StopPodSandbox(ctx, &runtimeapiv1.StopPodSandboxRequest{
+				PodSandboxId: podSandBoxID,
+			})
+		} else {
+			_, err = r.runtimeClient.StopPodSandbox(ctx, &runtimeapi.StopPodSandboxRequest{
+				PodSandboxId: podSandBoxID,
+			})
+		}
+		return err
 	})
 	if err != nil {
 		klog.Errorf("StopPodSandbox %q from runtime service failed: %v", podSandBoxID, err)
@@ -153,6 +444,7 @@ func (r *RemoteRuntimeService) StopPodSandbox(podSandBoxID string) error {
 	}
 
 	r.cache.deletePodSandBox(podSandBoxID)
+	r.forgetSandbox(podSandBoxID)
 	return nil
 }
 
@@ -162,8 +454,18 @@ func (r *RemoteRuntimeService) RemovePodSandbox(podSandBoxID string) error {
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	_, err := r.runtimeClient.RemovePodSandbox(ctx, &runtimeapi.RemovePodSandboxRequest{
-		PodSandboxId: podSandBoxID,
+	err := r.instrumented("RemovePodSandbox", func() error {
+		var err error
+		if r.apiVersion == CRIVersionV1 {
+			_, err = r.runtimeClientV1.RemovePodSandbox(ctx, &runtimeapiv1.RemovePodSandboxRequest{
+				PodSandboxId: podSandBoxID,
+			})
+		} else {
+			_, err = r.runtimeClient.RemovePodSandbox(ctx, &runtimeapi.RemovePodSandboxRequest{
+				PodSandboxId: podSandBoxID,
+			})
+		}
+		return err
 	})
 	if err != nil {
 		klog.Errorf("RemovePodSandbox %q from runtime service failed: %v", podSandBoxID, err)
@@ -171,6 +473,7 @@ func (r *RemoteRuntimeService) RemovePodSandbox(podSandBoxID string) error {
 	}
 
 	r.cache.deletePodSandBox(podSandBoxID)
+	r.forgetSandbox(podSandBoxID)
 	return nil
 }
 
@@ -179,20 +482,42 @@ func (r *RemoteRuntimeService) PodSandboxStatus(podSandBoxID string) (*runtimeap
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	resp, err := r.runtimeClient.PodSandboxStatus(ctx, &runtimeapi.PodSandboxStatusRequest{
-		PodSandboxId: podSandBoxID,
-	})
-	if err != nil {
-		return nil, err
+	var status *runtimeapi.PodSandboxStatus
+	if r.apiVersion == CRIVersionV1 {
+		var resp *runtimeapiv1.PodSandboxStatusResponse
+		err := r.instrumented("PodSandboxStatus", func() error {
+			var err error
+			resp, err = r.runtimeClientV1.PodSandboxStatus(ctx, &runtimeapiv1.PodSandboxStatusRequest{
+				PodSandboxId: podSandBoxID,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		status = types.ToV1alpha2PodSandboxStatus(types.FromV1PodSandboxStatus(resp.Status))
+	} else {
+		var resp *runtimeapi.PodSandboxStatusResponse
+		err := r.instrumented("PodSandboxStatus", func() error {
+			var err error
+			resp, err = r.runtimeClient.PodSandboxStatus(ctx, &runtimeapi.PodSandboxStatusRequest{
+				PodSandboxId: podSandBoxID,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		status = resp.Status
 	}
 
-	if resp.Status != nil {
-		if err := verifySandboxStatus(resp.Status); err != nil {
+	if status != nil {
+		if err := verifySandboxStatus(status); err != nil {
 			return nil, err
 		}
 	}
 
-	return resp.Status, nil
+	return status, nil
 }
 
 // ListPodSandbox returns a list of PodSandboxes.
@@ -200,8 +525,42 @@ func (r *RemoteRuntimeService) ListPodSandbox(filter *runtimeapi.PodSandboxFilte
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	resp, err := r.runtimeClient.ListPodSandbox(ctx, &runtimeapi.ListPodSandboxRequest{
-		Filter: filter,
+	if r.apiVersion == CRIVersionV1 {
+		var state *int32
+		if filter.GetState() != nil {
+			s := int32(filter.GetState().State)
+			state = &s
+		}
+		var resp *runtimeapiv1.ListPodSandboxResponse
+		err := r.instrumented("ListPodSandbox", func() error {
+			var err error
+			resp, err = r.runtimeClientV1.ListPodSandbox(ctx, &runtimeapiv1.ListPodSandboxRequest{
+				Filter: types.ToV1PodSandboxFilter(&types.PodSandboxFilter{
+					ID:            filter.GetId(),
+					State:         state,
+					LabelSelector: filter.GetLabelSelector(),
+				}),
+			})
+			return err
+		})
+		if err != nil {
+			klog.Errorf("ListPodSandbox with filter %+v from runtime service failed: %v", filter, err)
+			return nil, err
+		}
+		items := make([]*runtimeapi.PodSandbox, 0, len(resp.Items))
+		for _, item := range resp.Items {
+			items = append(items, types.ToV1alpha2PodSandbox(types.FromV1PodSandbox(item)))
+		}
+		return items, nil
+	}
+
+	var resp *runtimeapi.ListPodSandboxResponse
+	err := r.instrumented("ListPodSandbox", func() error {
+		var err error
+		resp, err = r.runtimeClient.ListPodSandbox(ctx, &runtimeapi.ListPodSandboxRequest{
+			Filter: filter,
+		})
+		return err
 	})
 	if err != nil {
 		klog.Errorf("ListPodSandbox with filter %+v from runtime service failed: %v", filter, err)
@@ -216,23 +575,59 @@ func (r *RemoteRuntimeService) CreateContainer(podSandBoxID string, config *runt
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	resp, err := r.runtimeClient.CreateContainer(ctx, &runtimeapi.CreateContainerRequest{
-		PodSandboxId:  podSandBoxID,
-		Config:        config,
-		SandboxConfig: sandboxConfig,
-	})
-	if err != nil {
-		klog.Errorf("CreateContainer in sandbox %q from runtime service failed: %v", podSandBoxID, err)
-		return "", err
+	var containerID string
+	if r.apiVersion == CRIVersionV1 {
+		v1Config, err := types.ConvertContainerConfigToV1(config)
+		if err != nil {
+			klog.Errorf("Failed to convert ContainerConfig to v1: %v", err)
+			return "", err
+		}
+		v1SandboxConfig, err := types.ConvertPodSandboxConfigToV1(sandboxConfig)
+		if err != nil {
+			klog.Errorf("Failed to convert PodSandboxConfig to v1: %v", err)
+			return "", err
+		}
+		var resp *runtimeapiv1.CreateContainerResponse
+		err = r.instrumented("CreateContainer", func() error {
+			var err error
+			resp, err = r.runtimeClientV1.CreateContainer(ctx, &runtimeapiv1.CreateContainerRequest{
+				PodSandboxId:  podSandBoxID,
+				Config:        v1Config,
+				SandboxConfig: v1SandboxConfig,
+			})
+			return err
+		})
+		if err != nil {
+			klog.Errorf("CreateContainer in sandbox %q from runtime service failed: %v", podSandBoxID, err)
+			return "", err
+		}
+		containerID = resp.ContainerId
+	} else {
+		var resp *runtimeapi.CreateContainerResponse
+		err := r.instrumented("CreateContainer", func() error {
+			var err error
+			resp, err = r.runtimeClient.CreateContainer(ctx, &runtimeapi.CreateContainerRequest{
+				PodSandboxId:  podSandBoxID,
+				Config:        config,
+				SandboxConfig: sandboxConfig,
+			})
+			return err
+		})
+		if err != nil {
+			klog.Errorf("CreateContainer in sandbox %q from runtime service failed: %v", podSandBoxID, err)
+			return "", err
+		}
+		containerID = resp.ContainerId
 	}
 
-	if resp.ContainerId == "" {
+	if containerID == "" {
 		errorMessage := fmt.Sprintf("ContainerId is not set for container %q", config.GetMetadata())
 		klog.Errorf("CreateContainer failed: %s", errorMessage)
 		return "", errors.New(errorMessage)
 	}
 
-	return resp.ContainerId, nil
+	r.cache.addContainer(containerID, podSandBoxID)
+	return containerID, nil
 }
 
 // StartContainer starts the container.
@@ -240,8 +635,18 @@ func (r *RemoteRuntimeService) StartContainer(containerID string) error {
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	_, err := r.runtimeClient.StartContainer(ctx, &runtimeapi.StartContainerRequest{
-		ContainerId: containerID,
+	err := r.instrumented("StartContainer", func() error {
+		var err error
+		if r.apiVersion == CRIVersionV1 {
+			_, err = r.runtimeClientV1.StartContainer(ctx, &runtimeapiv1.StartContainerRequest{
+				ContainerId: containerID,
+			})
+		} else {
+			_, err = r.runtimeClient.StartContainer(ctx, &runtimeapi.StartContainerRequest{
+				ContainerId: containerID,
+			})
+		}
+		return err
 	})
 	if err != nil {
 		klog.Errorf("StartContainer %q from runtime service failed: %v", containerID, err)
@@ -260,11 +665,26 @@ func (r *RemoteRuntimeService) StopContainer(containerID string, timeout int64)
 	defer cancel()
 
 	r.logReduction.ClearID(containerID)
-	_, err := r.runtimeClient.StopContainer(ctx, &runtimeapi.StopContainerRequest{
-		ContainerId: containerID,
-		Timeout:     timeout,
+	err := r.instrumented("StopContainer", func() error {
+		var err error
+		if r.apiVersion == CRIVersionV1 {
+			_, err = r.runtimeClientV1.StopContainer(ctx, &runtimeapiv1.StopContainerRequest{
+				ContainerId: containerID,
+				Timeout:     timeout,
+			})
+		} else {
+			_, err = r.runtimeClient.StopContainer(ctx, &runtimeapi.StopContainerRequest{
+				ContainerId: containerID,
+				Timeout:     timeout,
+			})
+		}
+		return err
 	})
 	if err != nil {
+		if criErrors.IsNotFound(err) {
+			r.onContainerVanished(containerID)
+			return nil
+		}
 		klog.Errorf("StopContainer %q from runtime service failed: %v", containerID, err)
 		return err
 	}
@@ -279,14 +699,29 @@ func (r *RemoteRuntimeService) RemoveContainer(containerID string) error {
 	defer cancel()
 
 	r.logReduction.ClearID(containerID)
-	_, err := r.runtimeClient.RemoveContainer(ctx, &runtimeapi.RemoveContainerRequest{
-		ContainerId: containerID,
+	err := r.instrumented("RemoveContainer", func() error {
+		var err error
+		if r.apiVersion == CRIVersionV1 {
+			_, err = r.runtimeClientV1.RemoveContainer(ctx, &runtimeapiv1.RemoveContainerRequest{
+				ContainerId: containerID,
+			})
+		} else {
+			_, err = r.runtimeClient.RemoveContainer(ctx, &runtimeapi.RemoveContainerRequest{
+				ContainerId: containerID,
+			})
+		}
+		return err
 	})
 	if err != nil {
+		if criErrors.IsNotFound(err) {
+			r.onContainerVanished(containerID)
+			return nil
+		}
 		klog.Errorf("RemoveContainer %q from runtime service failed: %v", containerID, err)
 		return err
 	}
 
+	r.cache.removeContainer(containerID)
 	return nil
 }
 
@@ -295,8 +730,43 @@ func (r *RemoteRuntimeService) ListContainers(filter *runtimeapi.ContainerFilter
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	resp, err := r.runtimeClient.ListContainers(ctx, &runtimeapi.ListContainersRequest{
-		Filter: filter,
+	if r.apiVersion == CRIVersionV1 {
+		var state *int32
+		if filter.GetState() != nil {
+			s := int32(filter.GetState().State)
+			state = &s
+		}
+		var resp *runtimeapiv1.ListContainersResponse
+		err := r.instrumented("ListContainers", func() error {
+			var err error
+			resp, err = r.runtimeClientV1.ListContainers(ctx, &runtimeapiv1.ListContainersRequest{
+				Filter: types.ToV1ContainerFilter(&types.ContainerFilter{
+					ID:            filter.GetId(),
+					State:         state,
+					PodSandboxID:  filter.GetPodSandboxId(),
+					LabelSelector: filter.GetLabelSelector(),
+				}),
+			})
+			return err
+		})
+		if err != nil {
+			klog.Errorf("ListContainers with filter %+v from runtime service failed: %v", filter, err)
+			return nil, err
+		}
+		containers := make([]*runtimeapi.Container, 0, len(resp.Containers))
+		for _, c := range resp.Containers {
+			containers = append(containers, types.ToV1alpha2Container(types.FromV1Container(c)))
+		}
+		return containers, nil
+	}
+
+	var resp *runtimeapi.ListContainersResponse
+	err := r.instrumented("ListContainers", func() error {
+		var err error
+		resp, err = r.runtimeClient.ListContainers(ctx, &runtimeapi.ListContainersRequest{
+			Filter: filter,
+		})
+		return err
 	})
 	if err != nil {
 		klog.Errorf("ListContainers with filter %+v from runtime service failed: %v", filter, err)
@@ -311,26 +781,60 @@ func (r *RemoteRuntimeService) ContainerStatus(containerID string) (*runtimeapi.
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	resp, err := r.runtimeClient.ContainerStatus(ctx, &runtimeapi.ContainerStatusRequest{
-		ContainerId: containerID,
-	})
-	if err != nil {
-		// Don't spam the log with endless messages about the same failure.
-		if r.logReduction.ShouldMessageBePrinted(err.Error(), containerID) {
-			klog.Errorf("ContainerStatus %q from runtime service failed: %v", containerID, err)
+	var status *runtimeapi.ContainerStatus
+	if r.apiVersion == CRIVersionV1 {
+		var resp *runtimeapiv1.ContainerStatusResponse
+		err := r.instrumented("ContainerStatus", func() error {
+			var err error
+			resp, err = r.runtimeClientV1.ContainerStatus(ctx, &runtimeapiv1.ContainerStatusRequest{
+				ContainerId: containerID,
+			})
+			return err
+		})
+		if err != nil {
+			if criErrors.IsNotFound(err) {
+				r.onContainerVanished(containerID)
+				return nil, err
+			}
+			if r.logReduction.ShouldMessageBePrinted(err.Error(), containerID) {
+				klog.Errorf("ContainerStatus %q from runtime service failed: %v", containerID, err)
+			}
+			return nil, err
 		}
-		return nil, err
+		r.logReduction.ClearID(containerID)
+		status = types.ToV1alpha2ContainerStatus(types.FromV1ContainerStatus(resp.Status))
+	} else {
+		var resp *runtimeapi.ContainerStatusResponse
+		err := r.instrumented("ContainerStatus", func() error {
+			var err error
+			resp, err = r.runtimeClient.ContainerStatus(ctx, &runtimeapi.ContainerStatusRequest{
+				ContainerId: containerID,
+			})
+			return err
+		})
+		if err != nil {
+			if criErrors.IsNotFound(err) {
+				r.onContainerVanished(containerID)
+				return nil, err
+			}
+			// Don't spam the log with endless messages about the same failure.
+			if r.logReduction.ShouldMessageBePrinted(err.Error(), containerID) {
+				klog.Errorf("ContainerStatus %q from runtime service failed: %v", containerID, err)
+			}
+			return nil, err
+		}
+		r.logReduction.ClearID(containerID)
+		status = resp.Status
 	}
-	r.logReduction.ClearID(containerID)
 
-	if resp.Status != nil {
-		if err := verifyContainerStatus(resp.Status); err != nil {
+	if status != nil {
+		if err := verifyContainerStatus(status); err != nil {
 			klog.Errorf("ContainerStatus of %q failed: %v", containerID, err)
 			return nil, err
 		}
 	}
 
-	return resp.Status, nil
+	return status, nil
 }
 
 // UpdateContainerResources updates a containers resource config
@@ -338,9 +842,20 @@ func (r *RemoteRuntimeService) UpdateContainerResources(containerID string, reso
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	_, err := r.runtimeClient.UpdateContainerResources(ctx, &runtimeapi.UpdateContainerResourcesRequest{
-		ContainerId: containerID,
-		Linux:       resources,
+	err := r.instrumented("UpdateContainerResources", func() error {
+		var err error
+		if r.apiVersion == CRIVersionV1 {
+			_, err = r.runtimeClientV1.UpdateContainerResources(ctx, &runtimeapiv1.UpdateContainerResourcesRequest{
+				ContainerId: containerID,
+				Linux:       types.ToV1LinuxContainerResources(types.FromV1alpha2LinuxContainerResources(resources)),
+			})
+		} else {
+			_, err = r.runtimeClient.UpdateContainerResources(ctx, &runtimeapi.UpdateContainerResourcesRequest{
+				ContainerId: containerID,
+				Linux:       resources,
+			})
+		}
+		return err
 	})
 	if err != nil {
 		klog.Errorf("UpdateContainerResources %q from runtime service failed: %v", containerID, err)
@@ -365,27 +880,26 @@ func (r *RemoteRuntimeService) ExecSync(containerID string, cmd []string, timeou
 	}
 	defer cancel()
 
-	timeoutSeconds := int64(timeout.Seconds())
-	req := &runtimeapi.ExecSyncRequest{
-		ContainerId: containerID,
-		Cmd:         cmd,
-		Timeout:     timeoutSeconds,
-	}
-	resp, err := r.runtimeClient.ExecSync(ctx, req)
+	var exitCode int32
+	err = r.instrumented("ExecSync", func() error {
+		var err error
+		stdout, stderr, exitCode, err = r.backend.ExecSync(ctx, containerID, cmd, timeout)
+		return err
+	})
 	if err != nil {
 		klog.Errorf("ExecSync %s '%s' from runtime service failed: %v", containerID, strings.Join(cmd, " "), err)
 		return nil, nil, err
 	}
 
 	err = nil
-	if resp.ExitCode != 0 {
+	if exitCode != 0 {
 		err = utilexec.CodeExitError{
-			Err:  fmt.Errorf("command '%s' exited with %d: %s", strings.Join(cmd, " "), resp.ExitCode, resp.Stderr),
-			Code: int(resp.ExitCode),
+			Err:  fmt.Errorf("command '%s' exited with %d: %s", strings.Join(cmd, " "), exitCode, stderr),
+			Code: int(exitCode),
 		}
 	}
 
-	return resp.Stdout, resp.Stderr, err
+	return stdout, stderr, err
 }
 
 // Exec prepares a streaming endpoint to execute a command in the container, and returns the address.
@@ -393,7 +907,12 @@ func (r *RemoteRuntimeService) Exec(req *runtimeapi.ExecRequest) (*runtimeapi.Ex
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	resp, err := r.runtimeClient.Exec(ctx, req)
+	var resp *runtimeapi.ExecResponse
+	err := r.instrumented("Exec", func() error {
+		var err error
+		resp, err = r.backend.Exec(ctx, req)
+		return err
+	})
 	if err != nil {
 		klog.Errorf("Exec %s '%s' from runtime service failed: %v", req.ContainerId, strings.Join(req.Cmd, " "), err)
 		return nil, err
@@ -413,7 +932,12 @@ func (r *RemoteRuntimeService) Attach(req *runtimeapi.AttachRequest) (*runtimeap
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	resp, err := r.runtimeClient.Attach(ctx, req)
+	var resp *runtimeapi.AttachResponse
+	err := r.instrumented("Attach", func() error {
+		var err error
+		resp, err = r.backend.Attach(ctx, req)
+		return err
+	})
 	if err != nil {
 		klog.Errorf("Attach %s from runtime service failed: %v", req.ContainerId, err)
 		return nil, err
@@ -432,7 +956,12 @@ func (r *RemoteRuntimeService) PortForward(req *runtimeapi.PortForwardRequest) (
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	resp, err := r.runtimeClient.PortForward(ctx, req)
+	var resp *runtimeapi.PortForwardResponse
+	err := r.instrumented("PortForward", func() error {
+		var err error
+		resp, err = r.backend.PortForward(ctx, req)
+		return err
+	})
 	if err != nil {
 		klog.Errorf("PortForward %s from runtime service failed: %v", req.PodSandboxId, err)
 		return nil, err
@@ -457,8 +986,18 @@ func (r *RemoteRuntimeService) UpdateRuntimeConfig(runtimeConfig *runtimeapi.Run
 	// Response doesn't contain anything of interest. This translates to an
 	// Event notification to the network plugin, which can't fail, so we're
 	// really looking to surface destination unreachable.
-	_, err := r.runtimeClient.UpdateRuntimeConfig(ctx, &runtimeapi.UpdateRuntimeConfigRequest{
-		RuntimeConfig: runtimeConfig,
+	err := r.instrumented("UpdateRuntimeConfig", func() error {
+		var err error
+		if r.apiVersion == CRIVersionV1 {
+			_, err = r.runtimeClientV1.UpdateRuntimeConfig(ctx, &runtimeapiv1.UpdateRuntimeConfigRequest{
+				RuntimeConfig: types.ToV1RuntimeConfig(types.FromV1alpha2RuntimeConfig(runtimeConfig)),
+			})
+		} else {
+			_, err = r.runtimeClient.UpdateRuntimeConfig(ctx, &runtimeapi.UpdateRuntimeConfigRequest{
+				RuntimeConfig: runtimeConfig,
+			})
+		}
+		return err
 	})
 
 	if err != nil {
@@ -473,19 +1012,24 @@ func (r *RemoteRuntimeService) Status() (*runtimeapi.RuntimeStatus, error) {
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	resp, err := r.runtimeClient.Status(ctx, &runtimeapi.StatusRequest{})
+	var status *runtimeapi.RuntimeStatus
+	err := r.instrumented("Status", func() error {
+		var err error
+		status, err = r.backend.Status(ctx)
+		return err
+	})
 	if err != nil {
 		klog.Errorf("Status from runtime service failed: %v", err)
 		return nil, err
 	}
 
-	if resp.Status == nil || len(resp.Status.Conditions) < 2 {
+	if status == nil || len(status.Conditions) < 2 {
 		errorMessage := "RuntimeReady or NetworkReady condition are not set"
 		klog.Errorf("Status failed: %s", errorMessage)
 		return nil, errors.New(errorMessage)
 	}
 
-	return resp.Status, nil
+	return status, nil
 }
 
 // ContainerStats returns the stats of the container.
@@ -493,10 +1037,17 @@ func (r *RemoteRuntimeService) ContainerStats(containerID string) (*runtimeapi.C
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	resp, err := r.runtimeClient.ContainerStats(ctx, &runtimeapi.ContainerStatsRequest{
-		ContainerId: containerID,
+	var stats *runtimeapi.ContainerStats
+	err := r.instrumented("ContainerStats", func() error {
+		var err error
+		stats, err = r.backend.ContainerStats(ctx, containerID)
+		return err
 	})
 	if err != nil {
+		if criErrors.IsNotFound(err) {
+			r.onContainerVanished(containerID)
+			return nil, err
+		}
 		if r.logReduction.ShouldMessageBePrinted(err.Error(), containerID) {
 			klog.Errorf("ContainerStatus %q from runtime service failed: %v", containerID, err)
 		}
@@ -504,7 +1055,7 @@ func (r *RemoteRuntimeService) ContainerStats(containerID string) (*runtimeapi.C
 	}
 	r.logReduction.ClearID(containerID)
 
-	return resp.GetStats(), nil
+	return stats, nil
 }
 
 func (r *RemoteRuntimeService) ListContainerStats(filter *runtimeapi.ContainerStatsFilter) ([]*runtimeapi.ContainerStats, error) {
@@ -513,8 +1064,33 @@ func (r *RemoteRuntimeService) ListContainerStats(filter *runtimeapi.ContainerSt
 	ctx, cancel := getContextWithCancel()
 	defer cancel()
 
-	resp, err := r.runtimeClient.ListContainerStats(ctx, &runtimeapi.ListContainerStatsRequest{
-		Filter: filter,
+	if r.apiVersion == CRIVersionV1 {
+		var resp *runtimeapiv1.ListContainerStatsResponse
+		err := r.instrumented("ListContainerStats", func() error {
+			var err error
+			resp, err = r.runtimeClientV1.ListContainerStats(ctx, &runtimeapiv1.ListContainerStatsRequest{
+				Filter: types.ToV1ContainerStatsFilter(types.FromV1alpha2ContainerStatsFilter(filter)),
+			})
+			return err
+		})
+		if err != nil {
+			klog.Errorf("ListContainerStats with filter %+v from runtime service failed: %v", filter, err)
+			return nil, err
+		}
+		stats := make([]*runtimeapi.ContainerStats, 0, len(resp.GetStats()))
+		for _, s := range resp.GetStats() {
+			stats = append(stats, types.ToV1alpha2ContainerStats(types.FromV1ContainerStats(s)))
+		}
+		return stats, nil
+	}
+
+	var resp *runtimeapi.ListContainerStatsResponse
+	err := r.instrumented("ListContainerStats", func() error {
+		var err error
+		resp, err = r.runtimeClient.ListContainerStats(ctx, &runtimeapi.ListContainerStatsRequest{
+			Filter: filter,
+		})
+		return err
 	})
 	if err != nil {
 		klog.Errorf("ListContainerStats with filter %+v from runtime service failed: %v", filter, err)
@@ -528,7 +1104,15 @@ func (r *RemoteRuntimeService) ReopenContainerLog(containerID string) error {
 	ctx, cancel := getContextWithTimeout(r.timeout)
 	defer cancel()
 
-	_, err := r.runtimeClient.ReopenContainerLog(ctx, &runtimeapi.ReopenContainerLogRequest{ContainerId: containerID})
+	err := r.instrumented("ReopenContainerLog", func() error {
+		var err error
+		if r.apiVersion == CRIVersionV1 {
+			_, err = r.runtimeClientV1.ReopenContainerLog(ctx, &runtimeapiv1.ReopenContainerLogRequest{ContainerId: containerID})
+		} else {
+			_, err = r.runtimeClient.ReopenContainerLog(ctx, &runtimeapi.ReopenContainerLogRequest{ContainerId: containerID})
+		}
+		return err
+	})
 	if err != nil {
 		klog.Errorf("ReopenContainerLog %q from runtime service failed: %v", containerID, err)
 		return err
@@ -536,7 +1120,81 @@ func (r *RemoteRuntimeService) ReopenContainerLog(containerID string) error {
 	return nil
 }
 
-func (r *RemoteRuntimeService) getPodRequest(name, namespace string) v1.ResourceList {
+// onContainerVanished handles a CRI NotFound response for a container the
+// simulated kubelet still believes is live: it clears log-reduction state for
+// the container so a future recreate isn't suppressed, evicts the container
+// from podSandBoxCache's container-to-sandbox tracking so it stops being
+// considered for stats and status lookups, and records a container-vanished
+// metric instead of logging at Error level, since this is an expected race
+// under high pod churn rather than a runtime failure.
+func (r *RemoteRuntimeService) onContainerVanished(containerID string) {
+	klog.V(4).Infof("container %q not found in runtime, treating as vanished", containerID)
+	r.logReduction.ClearID(containerID)
+	r.cache.removeContainer(containerID)
+	r.sink.LogContainerVanished(containerID)
+}
+
+// instrumented runs fn, a single CRI client call, and reports its latency
+// and outcome to the metrics sink under method before returning fn's error
+// unchanged, so call sites can wrap a runtime client call without altering
+// their own error handling.
+func (r *RemoteRuntimeService) instrumented(method string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	r.sink.LogCRICall(method, time.Since(start), err)
+	return err
+}
+
+// persistSandbox checkpoints the current cache entry for sandboxID so it
+// survives a restart. It is a no-op when checkpointing is disabled.
+func (r *RemoteRuntimeService) persistSandbox(sandboxID string) {
+	if r.checkpointer == nil {
+		return
+	}
+	info, ok := r.cache.snapshot()[sandboxID]
+	if !ok {
+		return
+	}
+	if err := r.checkpointer.Save(&checkpoint.Record{
+		SandboxID:      sandboxID,
+		Config:         info.Config,
+		StartAt:        info.StartAt,
+		Request:        info.Request,
+		PodDuration:    info.PodDuration,
+		PodTermination: info.PodTermination,
+		ExitCode:       info.ExitCode,
+	}); err != nil {
+		klog.Errorf("Failed to checkpoint pod sandbox %s: %v", sandboxID, err)
+	}
+}
+
+// forgetSandbox removes any on-disk checkpoint for sandboxID. It is a no-op
+// when checkpointing is disabled.
+func (r *RemoteRuntimeService) forgetSandbox(sandboxID string) {
+	if r.checkpointer == nil {
+		return
+	}
+	if err := r.checkpointer.Delete(sandboxID); err != nil {
+		klog.Errorf("Failed to remove checkpoint for pod sandbox %s: %v", sandboxID, err)
+	}
+}
+
+// getPodRequestAndSimSpec fetches the named pod once and derives both its
+// aggregate resource request and its simulated lifecycle spec from it,
+// instead of the two fetching the pod independently: RunPodSandbox is the
+// operation this simulator needs to be cheap, and it used to cost two API
+// server round trips per call for exactly this data.
+func (r *RemoteRuntimeService) getPodRequestAndSimSpec(name, namespace string) (v1.ResourceList, *podSimSpec) {
+	p, err := r.client.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		klog.Errorf("Failed to get pod %s/%s, %+v", namespace, name, err)
+		return nil, &podSimSpec{}
+	}
+	return podRequestFromPod(p), podSimSpecFromPod(p)
+}
+
+// podRequestFromPod sums the resource requests of p's containers.
+func podRequestFromPod(p *v1.Pod) v1.ResourceList {
 	request := v1.ResourceList{}
 
 	cpu := resource.MustParse("0")
@@ -544,11 +1202,6 @@ func (r *RemoteRuntimeService) getPodRequest(name, namespace string) v1.Resource
 	cpuPtr := &cpu
 	memoryPtr := &memory
 
-	p, err := r.client.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	if err != nil {
-		klog.Errorf("Failed to get pod %s/%s, %+v", namespace, name, err)
-		return nil
-	}
 	for _, c := range p.Spec.Containers {
 		for k, v := range c.Resources.Requests {
 			switch k {
@@ -567,6 +1220,54 @@ func (r *RemoteRuntimeService) getPodRequest(name, namespace string) v1.Resource
 	return request
 }
 
+// Annotations read off a pod spec to script its simulated lifecycle.
+const (
+	podSimDurationAnnotation    = "kubesim.volcano.sh/duration"
+	podSimTerminationAnnotation = "kubesim.volcano.sh/termination"
+	podSimExitCodeAnnotation    = "kubesim.volcano.sh/exit-code"
+)
+
+// podSimSpec is how long a simulated pod should run and how it should
+// terminate, sourced from its kubesim.volcano.sh/* annotations.
+type podSimSpec struct {
+	Duration    time.Duration
+	Termination string
+	ExitCode    int32
+}
+
+// podSimSpecFromPod reads the simulated lifecycle annotations off p. A
+// missing or unparsable annotation leaves the corresponding field at its
+// zero value, which podHouseKeeping treats as "run forever" for Duration and
+// "Completed with exit code 0" for Termination/ExitCode.
+func podSimSpecFromPod(p *v1.Pod) *podSimSpec {
+	spec := &podSimSpec{}
+	name, namespace := p.Name, p.Namespace
+
+	if v, ok := p.Annotations[podSimDurationAnnotation]; ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			klog.Errorf("Invalid %s annotation %q on pod %s/%s: %v", podSimDurationAnnotation, v, namespace, name, err)
+		} else {
+			spec.Duration = d
+		}
+	}
+
+	if v, ok := p.Annotations[podSimTerminationAnnotation]; ok {
+		spec.Termination = v
+	}
+
+	if v, ok := p.Annotations[podSimExitCodeAnnotation]; ok {
+		code, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			klog.Errorf("Invalid %s annotation %q on pod %s/%s: %v", podSimExitCodeAnnotation, v, namespace, name, err)
+		} else {
+			spec.ExitCode = int32(code)
+		}
+	}
+
+	return spec
+}
+
 // set pod ternimation status for simulation
 func (r *RemoteRuntimeService) podHouseKeeping() {
 	podSandbox := r.cache.snapshot()
@@ -580,22 +1281,58 @@ func (r *RemoteRuntimeService) podHouseKeeping() {
 		podClient := r.client.CoreV1().Pods(sandbox.Config.Metadata.Namespace)
 		p, err := podClient.Get(context.TODO(), sandbox.Config.Metadata.Name, metav1.GetOptions{})
 		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// The pod was deleted out from under us; nothing left to
+				// reconcile, so just drop the stale sandbox entry.
+				r.cache.deletePodSandBox(id)
+				r.forgetSandbox(id)
+				continue
+			}
 			klog.Errorf("Failed to get pod %s/%s", sandbox.Config.Metadata.Namespace, sandbox.Config.Metadata.Name)
 			continue
 		}
 
+		if sandbox.PodTermination == "Restart" {
+			copy := *p
+			copy.Status.Phase = v1.PodRunning
+			for index := range copy.Status.ContainerStatuses {
+				copy.Status.ContainerStatuses[index].RestartCount++
+				copy.Status.ContainerStatuses[index].Ready = true
+				started := true
+				copy.Status.ContainerStatuses[index].Started = &started
+				copy.Status.ContainerStatuses[index].State = v1.ContainerState{
+					Running: &v1.ContainerStateRunning{StartedAt: metav1.NewTime(time.Now())},
+				}
+			}
+			if _, err := podClient.UpdateStatus(context.TODO(), &copy, metav1.UpdateOptions{}); err != nil {
+				klog.Errorf("Failed to update pod %s/%s status", sandbox.Config.Metadata.Namespace, sandbox.Config.Metadata.Name)
+				continue
+			}
+			sandbox.StartAt = time.Now()
+			r.persistSandbox(id)
+			r.sink.LogPodLifecycle("PodRestarted", id, sandbox.Config.Metadata.Namespace, sandbox.Config.Metadata.Name, 0, string(v1.PodRunning), time.Now())
+			continue
+		}
+
 		phase := v1.PodSucceeded
-		exitCode := int32(0)
+		exitCode := sandbox.ExitCode
 		reason := "Completed"
 		switch sandbox.PodTermination {
 		case "Succeeded":
 			phase = v1.PodSucceeded
-			exitCode = 0
 			reason = "Completed"
 		case "Failed":
 			phase = v1.PodFailed
-			exitCode = 1
+			if exitCode == 0 {
+				exitCode = 1
+			}
 			reason = "Failed"
+		case "OOMKilled":
+			phase = v1.PodFailed
+			if exitCode == 0 {
+				exitCode = 137
+			}
+			reason = "OOMKilled"
 		}
 		copy := *p
 		copy.Status.Phase = phase
@@ -626,6 +1363,8 @@ func (r *RemoteRuntimeService) podHouseKeeping() {
 		}
 
 		r.cache.deletePodSandBox(id)
+		r.forgetSandbox(id)
+		r.sink.LogPodLifecycle("PodTerminated", id, sandbox.Config.Metadata.Namespace, sandbox.Config.Metadata.Name, exitCode, string(phase), time.Now())
 	}
 }
 
@@ -0,0 +1,247 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+
+	"volcano.sh/kubesim/pkg/metrics"
+)
+
+// fakeSink is a no-op metrics.Interface for tests that exercise code paths
+// which report through RemoteRuntimeService.sink but don't assert on it.
+type fakeSink struct{}
+
+func (fakeSink) LogNodeMetrics(nm *metrics.NodeMetric)                {}
+func (fakeSink) LogContainerVanished(containerID string)              {}
+func (fakeSink) LogCRICall(method string, d time.Duration, err error) {}
+func (fakeSink) LogPodLifecycle(event string, sandboxID, namespace, name string, exitCode int32, phase string, ts time.Time) {
+}
+
+func newTestService(pod *v1.Pod) *RemoteRuntimeService {
+	return &RemoteRuntimeService{client: fake.NewSimpleClientset(pod)}
+}
+
+func testPod(annotations map[string]string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-pod",
+			Namespace:   "default",
+			Annotations: annotations,
+		},
+	}
+}
+
+func TestPodSimSpecFromPodParsesAnnotations(t *testing.T) {
+	pod := testPod(map[string]string{
+		podSimDurationAnnotation:    "30s",
+		podSimTerminationAnnotation: "Failed",
+		podSimExitCodeAnnotation:    "137",
+	})
+
+	spec := podSimSpecFromPod(pod)
+
+	if spec.Duration != 30*time.Second {
+		t.Errorf("Duration = %v, want 30s", spec.Duration)
+	}
+	if spec.Termination != "Failed" {
+		t.Errorf("Termination = %q, want %q", spec.Termination, "Failed")
+	}
+	if spec.ExitCode != 137 {
+		t.Errorf("ExitCode = %d, want 137", spec.ExitCode)
+	}
+}
+
+func TestPodSimSpecFromPodMissingAnnotationsDefaultToZeroValue(t *testing.T) {
+	spec := podSimSpecFromPod(testPod(nil))
+
+	if spec.Duration != 0 {
+		t.Errorf("Duration = %v, want 0 (run forever)", spec.Duration)
+	}
+	if spec.Termination != "" {
+		t.Errorf("Termination = %q, want empty", spec.Termination)
+	}
+	if spec.ExitCode != 0 {
+		t.Errorf("ExitCode = %d, want 0", spec.ExitCode)
+	}
+}
+
+func TestPodSimSpecFromPodInvalidAnnotationsAreIgnored(t *testing.T) {
+	pod := testPod(map[string]string{
+		podSimDurationAnnotation: "not-a-duration",
+		podSimExitCodeAnnotation: "not-a-number",
+	})
+
+	spec := podSimSpecFromPod(pod)
+
+	if spec.Duration != 0 {
+		t.Errorf("Duration = %v, want 0 for unparsable annotation", spec.Duration)
+	}
+	if spec.ExitCode != 0 {
+		t.Errorf("ExitCode = %d, want 0 for unparsable annotation", spec.ExitCode)
+	}
+}
+
+func TestGetPodRequestAndSimSpecMissingPodReturnsZeroValues(t *testing.T) {
+	r := newTestService(testPod(nil))
+
+	request, spec := r.getPodRequestAndSimSpec("does-not-exist", "default")
+
+	if request != nil {
+		t.Errorf("request = %+v, want nil for a missing pod", request)
+	}
+	if spec.Duration != 0 || spec.Termination != "" || spec.ExitCode != 0 {
+		t.Errorf("getPodRequestAndSimSpec for a missing pod = %+v, want zero-value spec", spec)
+	}
+}
+
+// testSandbox builds a podSandBoxInfo for the given pod name/namespace,
+// started duration ago so podHouseKeeping always treats it as due.
+func testSandbox(name, namespace, termination string, exitCode int32) *podSandBoxInfo {
+	return &podSandBoxInfo{
+		Config: &runtimeapi.PodSandboxConfig{
+			Metadata: &runtimeapi.PodSandboxMetadata{Name: name, Namespace: namespace},
+		},
+		StartAt:        time.Now().Add(-time.Hour),
+		PodDuration:    time.Minute,
+		PodTermination: termination,
+		ExitCode:       exitCode,
+	}
+}
+
+func TestPodHouseKeeping(t *testing.T) {
+	tests := []struct {
+		name         string
+		termination  string
+		exitCode     int32
+		wantPhase    v1.PodPhase
+		wantExitCode int32
+		wantDeleted  bool
+	}{
+		{
+			name:         "Succeeded",
+			termination:  "Succeeded",
+			exitCode:     0,
+			wantPhase:    v1.PodSucceeded,
+			wantExitCode: 0,
+			wantDeleted:  true,
+		},
+		{
+			name:         "Failed defaults exit code to 1",
+			termination:  "Failed",
+			exitCode:     0,
+			wantPhase:    v1.PodFailed,
+			wantExitCode: 1,
+			wantDeleted:  true,
+		},
+		{
+			name:         "OOMKilled defaults exit code to 137",
+			termination:  "OOMKilled",
+			exitCode:     0,
+			wantPhase:    v1.PodFailed,
+			wantExitCode: 137,
+			wantDeleted:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default"},
+				Status: v1.PodStatus{
+					ContainerStatuses: []v1.ContainerStatus{{Name: "c"}},
+				},
+			}
+			cache := &podSandBoxCache{PodSandBox: map[string]*podSandBoxInfo{
+				"sandbox-1": testSandbox("test-pod", "default", tc.termination, tc.exitCode),
+			}}
+			r := &RemoteRuntimeService{
+				client: fake.NewSimpleClientset(pod),
+				cache:  cache,
+				sink:   fakeSink{},
+			}
+
+			r.podHouseKeeping()
+
+			_, stillCached := cache.PodSandBox["sandbox-1"]
+			if stillCached == tc.wantDeleted {
+				t.Errorf("sandbox cached after podHouseKeeping = %v, want deleted = %v", stillCached, tc.wantDeleted)
+			}
+
+			got, err := r.client.CoreV1().Pods("default").Get(context.TODO(), "test-pod", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Get updated pod: %v", err)
+			}
+			if got.Status.Phase != tc.wantPhase {
+				t.Errorf("Phase = %v, want %v", got.Status.Phase, tc.wantPhase)
+			}
+			term := got.Status.ContainerStatuses[0].State.Terminated
+			if term == nil {
+				t.Fatalf("ContainerStatuses[0].State.Terminated is nil")
+			}
+			if term.ExitCode != tc.wantExitCode {
+				t.Errorf("ExitCode = %d, want %d", term.ExitCode, tc.wantExitCode)
+			}
+		})
+	}
+}
+
+func TestPodHouseKeepingRestart(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default"},
+		Status: v1.PodStatus{
+			Phase:             v1.PodFailed,
+			ContainerStatuses: []v1.ContainerStatus{{Name: "c", RestartCount: 2}},
+		},
+	}
+	sandbox := testSandbox("test-pod", "default", "Restart", 0)
+	staleStartAt := sandbox.StartAt
+	cache := &podSandBoxCache{PodSandBox: map[string]*podSandBoxInfo{"sandbox-1": sandbox}}
+	r := &RemoteRuntimeService{
+		client: fake.NewSimpleClientset(pod),
+		cache:  cache,
+		sink:   fakeSink{},
+	}
+
+	r.podHouseKeeping()
+
+	cached, ok := cache.PodSandBox["sandbox-1"]
+	if !ok {
+		t.Fatalf("sandbox evicted from cache on Restart, want retained")
+	}
+	if !cached.StartAt.After(staleStartAt) {
+		t.Errorf("StartAt = %v, want reset to after %v", cached.StartAt, staleStartAt)
+	}
+
+	got, err := r.client.CoreV1().Pods("default").Get(context.TODO(), "test-pod", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get updated pod: %v", err)
+	}
+	if got.Status.Phase != v1.PodRunning {
+		t.Errorf("Phase = %v, want %v", got.Status.Phase, v1.PodRunning)
+	}
+	if got.Status.ContainerStatuses[0].RestartCount != 3 {
+		t.Errorf("RestartCount = %d, want 3", got.Status.ContainerStatuses[0].RestartCount)
+	}
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package checkpoint persists the simulated kubelet's pod sandbox cache to
+// disk so a restart of RemoteRuntimeService doesn't lose in-flight pods'
+// remaining simulated duration or the node allocation totals derived from
+// them. Each sandbox is written to its own <sandboxID>.json file, plus a
+// manifest.json listing the active sandbox IDs, using write-to-temp-then-
+// rename so a crash mid-write never leaves a corrupt record behind.
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+)
+
+const manifestFile = "manifest.json"
+
+// Record is the on-disk representation of a single pod sandbox's simulation
+// state: enough to rehydrate podSandBoxCache after a restart without
+// re-querying anything other than whether the sandbox still exists.
+type Record struct {
+	SandboxID      string                       `json:"sandboxId"`
+	Config         *runtimeapi.PodSandboxConfig `json:"config"`
+	StartAt        time.Time                    `json:"startAt"`
+	Request        v1.ResourceList              `json:"request"`
+	PodDuration    time.Duration                `json:"podDuration"`
+	PodTermination string                       `json:"podTermination"`
+	ExitCode       int32                        `json:"exitCode"`
+}
+
+type manifest struct {
+	SandboxIDs []string `json:"sandboxIds"`
+}
+
+// Checkpointer reads and writes pod sandbox Records under a directory. A
+// zero-value dir disables persistence: every method becomes a no-op so
+// callers don't need to special-case "checkpointing turned off".
+type Checkpointer struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewCheckpointer returns a Checkpointer rooted at dir. Pass an empty dir to
+// disable checkpointing.
+func NewCheckpointer(dir string) *Checkpointer {
+	return &Checkpointer{dir: dir}
+}
+
+// Save atomically persists rec and adds it to the manifest.
+func (c *Checkpointer) Save(rec *Record) error {
+	if c.dir == "" {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create checkpoint dir %s: %w", c.dir, err)
+	}
+	if err := writeJSONAtomic(c.recordPath(rec.SandboxID), rec); err != nil {
+		return fmt.Errorf("failed to checkpoint pod sandbox %s: %w", rec.SandboxID, err)
+	}
+	return c.updateManifestLocked(func(ids map[string]bool) { ids[rec.SandboxID] = true })
+}
+
+// Delete removes the checkpoint for sandboxID, if any, and drops it from the
+// manifest.
+func (c *Checkpointer) Delete(sandboxID string) error {
+	if c.dir == "" {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.Remove(c.recordPath(sandboxID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove checkpoint for pod sandbox %s: %w", sandboxID, err)
+	}
+	return c.updateManifestLocked(func(ids map[string]bool) { delete(ids, sandboxID) })
+}
+
+// Sync overwrites the checkpoint directory with exactly the given records,
+// dropping any checkpoint that isn't present in records. Used for the
+// periodic full flush and shutdown hooks.
+func (c *Checkpointer) Sync(records map[string]*Record) error {
+	if c.dir == "" {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create checkpoint dir %s: %w", c.dir, err)
+	}
+
+	previous, err := c.readManifestLocked()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	ids := make(map[string]bool, len(records))
+	for id, rec := range records {
+		if err := writeJSONAtomic(c.recordPath(id), rec); err != nil {
+			return fmt.Errorf("failed to checkpoint pod sandbox %s: %w", id, err)
+		}
+		ids[id] = true
+	}
+	for id := range previous {
+		if ids[id] {
+			continue
+		}
+		if err := os.Remove(c.recordPath(id)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale checkpoint for pod sandbox %s: %w", id, err)
+		}
+	}
+	return c.writeManifestLocked(ids)
+}
+
+// LoadAll reads every Record listed in the manifest. Records whose file is
+// missing or unreadable are skipped rather than failing the whole load, so a
+// single corrupt checkpoint doesn't block startup.
+func (c *Checkpointer) LoadAll() (map[string]*Record, error) {
+	records := make(map[string]*Record)
+	if c.dir == "" {
+		return records, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ids, err := c.readManifestLocked()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, err
+	}
+	for id := range ids {
+		data, err := os.ReadFile(c.recordPath(id))
+		if err != nil {
+			continue
+		}
+		rec := &Record{}
+		if err := json.Unmarshal(data, rec); err != nil {
+			continue
+		}
+		records[id] = rec
+	}
+	return records, nil
+}
+
+func (c *Checkpointer) recordPath(sandboxID string) string {
+	return filepath.Join(c.dir, sandboxID+".json")
+}
+
+func (c *Checkpointer) manifestPath() string {
+	return filepath.Join(c.dir, manifestFile)
+}
+
+func (c *Checkpointer) readManifestLocked() (map[string]bool, error) {
+	data, err := os.ReadFile(c.manifestPath())
+	if err != nil {
+		return nil, err
+	}
+	m := &manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint manifest %s: %w", c.manifestPath(), err)
+	}
+	ids := make(map[string]bool, len(m.SandboxIDs))
+	for _, id := range m.SandboxIDs {
+		ids[id] = true
+	}
+	return ids, nil
+}
+
+func (c *Checkpointer) updateManifestLocked(mutate func(ids map[string]bool)) error {
+	ids, err := c.readManifestLocked()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		ids = map[string]bool{}
+	}
+	mutate(ids)
+	return c.writeManifestLocked(ids)
+}
+
+func (c *Checkpointer) writeManifestLocked(ids map[string]bool) error {
+	list := make([]string, 0, len(ids))
+	for id := range ids {
+		list = append(list, id)
+	}
+	sort.Strings(list)
+	if err := writeJSONAtomic(c.manifestPath(), &manifest{SandboxIDs: list}); err != nil {
+		return fmt.Errorf("failed to write checkpoint manifest %s: %w", c.manifestPath(), err)
+	}
+	return nil
+}
+
+// writeJSONAtomic marshals v and writes it to path by first writing to a
+// temp file in the same directory, then renaming over path, so a reader
+// never observes a partially written file.
+func writeJSONAtomic(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+)
+
+func testRecord(sandboxID string) *Record {
+	return &Record{
+		SandboxID: sandboxID,
+		Config: &runtimeapi.PodSandboxConfig{
+			Metadata: &runtimeapi.PodSandboxMetadata{Name: sandboxID},
+		},
+		StartAt: time.Unix(1000, 0),
+		Request: v1.ResourceList{
+			v1.ResourceCPU: resource.MustParse("1"),
+		},
+		PodDuration:    time.Minute,
+		PodTermination: "Completed",
+		ExitCode:       0,
+	}
+}
+
+func TestCheckpointerSaveAndLoadAll(t *testing.T) {
+	c := NewCheckpointer(t.TempDir())
+
+	if err := c.Save(testRecord("sandbox-a")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := c.Save(testRecord("sandbox-b")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	records, err := c.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("LoadAll returned %d records, want 2", len(records))
+	}
+	got, ok := records["sandbox-a"]
+	if !ok {
+		t.Fatalf("LoadAll missing sandbox-a")
+	}
+	if got.Config.Metadata.Name != "sandbox-a" || got.PodTermination != "Completed" {
+		t.Fatalf("LoadAll returned unexpected record: %+v", got)
+	}
+}
+
+func TestCheckpointerDelete(t *testing.T) {
+	c := NewCheckpointer(t.TempDir())
+
+	if err := c.Save(testRecord("sandbox-a")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := c.Delete("sandbox-a"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	records, err := c.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("LoadAll returned %d records after Delete, want 0", len(records))
+	}
+
+	// Deleting a sandbox that was never saved is a no-op, not an error.
+	if err := c.Delete("never-existed"); err != nil {
+		t.Fatalf("Delete of unknown sandbox: %v", err)
+	}
+}
+
+func TestCheckpointerSyncDropsStaleRecords(t *testing.T) {
+	c := NewCheckpointer(t.TempDir())
+
+	if err := c.Save(testRecord("sandbox-a")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := c.Save(testRecord("sandbox-b")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := c.Sync(map[string]*Record{"sandbox-b": testRecord("sandbox-b")}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	records, err := c.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	if _, ok := records["sandbox-a"]; ok {
+		t.Fatalf("Sync left stale record sandbox-a on disk")
+	}
+	if _, ok := records["sandbox-b"]; !ok {
+		t.Fatalf("Sync dropped sandbox-b, which was still in the synced set")
+	}
+}
+
+func TestCheckpointerLoadAllSkipsCorruptRecord(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCheckpointer(dir)
+
+	if err := c.Save(testRecord("sandbox-a")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// A corrupt record file should be skipped rather than failing the load.
+	if err := writeJSONAtomic(filepath.Join(dir, "sandbox-b.json"), "not a valid record"); err != nil {
+		t.Fatalf("writeJSONAtomic: %v", err)
+	}
+	if err := c.updateManifestLocked(func(ids map[string]bool) { ids["sandbox-b"] = true }); err != nil {
+		t.Fatalf("updateManifestLocked: %v", err)
+	}
+
+	records, err := c.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	if _, ok := records["sandbox-a"]; !ok {
+		t.Fatalf("LoadAll dropped the valid sandbox-a record")
+	}
+	if _, ok := records["sandbox-b"]; ok {
+		t.Fatalf("LoadAll returned the corrupt sandbox-b record")
+	}
+}
+
+func TestCheckpointerDisabledWithEmptyDir(t *testing.T) {
+	c := NewCheckpointer("")
+
+	if err := c.Save(testRecord("sandbox-a")); err != nil {
+		t.Fatalf("Save on disabled checkpointer: %v", err)
+	}
+	records, err := c.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll on disabled checkpointer: %v", err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("LoadAll on disabled checkpointer returned %d records, want 0", len(records))
+	}
+}
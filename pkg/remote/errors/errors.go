@@ -0,0 +1,43 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package errors classifies errors returned by the CRI runtime client so
+// callers can tell a hard failure from a transient, expected condition.
+package errors
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IsNotFound reports whether err represents a CRI "not found" condition,
+// e.g. a ContainerStatus call racing a concurrent RemoveContainer. It relies
+// solely on the gRPC codes.NotFound status: containerd and cri-o, the two
+// runtimes RemoteRuntimeService targets, both surface CRI NotFound errors
+// through a gRPC status rather than a plain-text message, and fakeBackend's
+// synthetic responses never produce a "not found" at all. A prior plain-text
+// "not found" substring fallback was removed because it reclassified
+// unrelated failures (e.g. "failed to mount volume: device not found") as a
+// soft vanished-container signal. If a non-gRPC CRI shim needs supporting
+// later, add it here by exact, narrow error string rather than a generic
+// substring match.
+func IsNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	s, ok := status.FromError(err)
+	return ok && s.Code() == codes.NotFound
+}
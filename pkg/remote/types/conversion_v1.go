@@ -0,0 +1,284 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	runtimeapiv1alpha2 "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+)
+
+// ConvertPodSandboxConfigToV1 converts a v1alpha2 PodSandboxConfig to its v1
+// wire type by round-tripping it through the wire encoding rather than a
+// hand-maintained field list. CRI's v1 PodSandboxConfig was introduced as a
+// field-number-for-field-number promotion of v1alpha2's, so the two stay
+// wire-compatible; marshaling through the encoding instead of copying fields
+// one by one means DnsConfig, PortMappings, Linux, Windows and anything else
+// added to either message later all survive the conversion, instead of
+// silently dropping whatever a hand-written copy doesn't happen to list.
+func ConvertPodSandboxConfigToV1(in *runtimeapiv1alpha2.PodSandboxConfig) (*runtimeapi.PodSandboxConfig, error) {
+	if in == nil {
+		return nil, nil
+	}
+	data, err := proto.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	out := &runtimeapi.PodSandboxConfig{}
+	if err := proto.Unmarshal(data, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConvertContainerConfigToV1 converts a v1alpha2 ContainerConfig to its v1
+// wire type the same way ConvertPodSandboxConfigToV1 does, for the same
+// reason: Mounts, Devices, LogPath, Stdin/Tty and Linux/Windows settings all
+// matter for actually running the container and must not be dropped.
+func ConvertContainerConfigToV1(in *runtimeapiv1alpha2.ContainerConfig) (*runtimeapi.ContainerConfig, error) {
+	if in == nil {
+		return nil, nil
+	}
+	data, err := proto.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	out := &runtimeapi.ContainerConfig{}
+	if err := proto.Unmarshal(data, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FromV1PodSandboxStatus converts a v1 PodSandboxStatus to its internal mirror.
+func FromV1PodSandboxStatus(in *runtimeapi.PodSandboxStatus) *PodSandboxStatus {
+	if in == nil {
+		return nil
+	}
+	out := &PodSandboxStatus{
+		ID:        in.Id,
+		State:     int32(in.State),
+		CreatedAt: in.CreatedAt,
+		Labels:    in.Labels,
+	}
+	if in.Metadata != nil {
+		out.Metadata = &PodSandboxMetadata{
+			Name:      in.Metadata.Name,
+			Namespace: in.Metadata.Namespace,
+			UID:       in.Metadata.Uid,
+			Attempt:   in.Metadata.Attempt,
+		}
+	}
+	if in.Network != nil {
+		out.Network = &PodSandboxNetworkStatus{IP: in.Network.Ip}
+	}
+	return out
+}
+
+// FromV1PodSandbox converts a v1 PodSandbox to its internal mirror.
+func FromV1PodSandbox(in *runtimeapi.PodSandbox) *PodSandbox {
+	if in == nil {
+		return nil
+	}
+	out := &PodSandbox{
+		ID:          in.Id,
+		State:       int32(in.State),
+		CreatedAt:   in.CreatedAt,
+		Labels:      in.Labels,
+		Annotations: in.Annotations,
+	}
+	if in.Metadata != nil {
+		out.Metadata = &PodSandboxMetadata{
+			Name:      in.Metadata.Name,
+			Namespace: in.Metadata.Namespace,
+			UID:       in.Metadata.Uid,
+			Attempt:   in.Metadata.Attempt,
+		}
+	}
+	return out
+}
+
+// ToV1PodSandboxFilter converts the internal PodSandboxFilter mirror to its v1 wire type.
+func ToV1PodSandboxFilter(in *PodSandboxFilter) *runtimeapi.PodSandboxFilter {
+	if in == nil {
+		return nil
+	}
+	out := &runtimeapi.PodSandboxFilter{
+		Id:            in.ID,
+		LabelSelector: in.LabelSelector,
+	}
+	if in.State != nil {
+		out.State = &runtimeapi.PodSandboxStateValue{State: runtimeapi.PodSandboxState(*in.State)}
+	}
+	return out
+}
+
+// ToV1LinuxContainerResources converts the internal LinuxContainerResources mirror to its v1 wire type.
+func ToV1LinuxContainerResources(in *LinuxContainerResources) *runtimeapi.LinuxContainerResources {
+	if in == nil {
+		return nil
+	}
+	return &runtimeapi.LinuxContainerResources{
+		CpuPeriod:          in.CPUPeriod,
+		CpuQuota:           in.CPUQuota,
+		CpuShares:          in.CPUShares,
+		MemoryLimitInBytes: in.MemoryLimitInBytes,
+		OomScoreAdj:        in.OomScoreAdj,
+		CpusetCpus:         in.CpusetCpus,
+		CpusetMems:         in.CpusetMems,
+	}
+}
+
+// FromV1ContainerStatus converts a v1 ContainerStatus to its internal mirror.
+func FromV1ContainerStatus(in *runtimeapi.ContainerStatus) *ContainerStatus {
+	if in == nil {
+		return nil
+	}
+	out := &ContainerStatus{
+		ID:          in.Id,
+		State:       int32(in.State),
+		CreatedAt:   in.CreatedAt,
+		StartedAt:   in.StartedAt,
+		FinishedAt:  in.FinishedAt,
+		ExitCode:    in.ExitCode,
+		Reason:      in.Reason,
+		Message:     in.Message,
+		Labels:      in.Labels,
+		Annotations: in.Annotations,
+	}
+	if in.Metadata != nil {
+		out.Metadata = &ContainerMetadata{Name: in.Metadata.Name, Attempt: in.Metadata.Attempt}
+	}
+	if in.Image != nil {
+		out.Image = &ImageSpec{Image: in.Image.Image}
+	}
+	return out
+}
+
+// FromV1Container converts a v1 Container to its internal mirror.
+func FromV1Container(in *runtimeapi.Container) *Container {
+	if in == nil {
+		return nil
+	}
+	out := &Container{
+		ID:           in.Id,
+		PodSandboxID: in.PodSandboxId,
+		State:        int32(in.State),
+		CreatedAt:    in.CreatedAt,
+		Labels:       in.Labels,
+		Annotations:  in.Annotations,
+	}
+	if in.Metadata != nil {
+		out.Metadata = &ContainerMetadata{Name: in.Metadata.Name, Attempt: in.Metadata.Attempt}
+	}
+	if in.Image != nil {
+		out.Image = &ImageSpec{Image: in.Image.Image}
+	}
+	return out
+}
+
+// ToV1ContainerFilter converts the internal ContainerFilter mirror to its v1 wire type.
+func ToV1ContainerFilter(in *ContainerFilter) *runtimeapi.ContainerFilter {
+	if in == nil {
+		return nil
+	}
+	out := &runtimeapi.ContainerFilter{
+		Id:            in.ID,
+		PodSandboxId:  in.PodSandboxID,
+		LabelSelector: in.LabelSelector,
+	}
+	if in.State != nil {
+		out.State = &runtimeapi.ContainerStateValue{State: runtimeapi.ContainerState(*in.State)}
+	}
+	return out
+}
+
+// ToV1ContainerStatsFilter converts the internal ContainerStatsFilter mirror to its v1 wire type.
+func ToV1ContainerStatsFilter(in *ContainerStatsFilter) *runtimeapi.ContainerStatsFilter {
+	if in == nil {
+		return nil
+	}
+	return &runtimeapi.ContainerStatsFilter{
+		Id:            in.ID,
+		PodSandboxId:  in.PodSandboxID,
+		LabelSelector: in.LabelSelector,
+	}
+}
+
+// FromV1ContainerStats converts a v1 ContainerStats to its internal mirror.
+func FromV1ContainerStats(in *runtimeapi.ContainerStats) *ContainerStats {
+	if in == nil {
+		return nil
+	}
+	out := &ContainerStats{}
+	if in.Attributes != nil {
+		out.Attributes = &ContainerAttributes{ID: in.Attributes.Id}
+	}
+	if in.Cpu != nil {
+		out.CPU = &CPUUsage{Timestamp: in.Cpu.Timestamp, UsageCoreNanoSeconds: in.Cpu.UsageCoreNanoSeconds.GetValue()}
+	}
+	if in.Memory != nil {
+		out.Memory = &MemoryUsage{Timestamp: in.Memory.Timestamp, WorkingSetBytes: in.Memory.WorkingSetBytes.GetValue()}
+	}
+	if in.WritableLayer != nil {
+		out.WritableLayer = &FilesystemUsage{Timestamp: in.WritableLayer.Timestamp, UsedBytes: in.WritableLayer.UsedBytes.GetValue()}
+	}
+	return out
+}
+
+// FromV1RuntimeStatus converts a v1 RuntimeStatus to its internal mirror.
+func FromV1RuntimeStatus(in *runtimeapi.RuntimeStatus) *RuntimeStatus {
+	if in == nil {
+		return nil
+	}
+	out := &RuntimeStatus{}
+	for _, c := range in.Conditions {
+		out.Conditions = append(out.Conditions, &RuntimeCondition{
+			Type:    c.Type,
+			Status:  c.Status,
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+	}
+	return out
+}
+
+// ToV1RuntimeConfig converts the internal RuntimeConfig mirror to its v1 wire type.
+func ToV1RuntimeConfig(in *RuntimeConfig) *runtimeapi.RuntimeConfig {
+	if in == nil {
+		return nil
+	}
+	out := &runtimeapi.RuntimeConfig{}
+	if in.NetworkConfig != nil {
+		out.NetworkConfig = &runtimeapi.NetworkConfig{PodCidr: in.NetworkConfig.PodCIDR}
+	}
+	return out
+}
+
+// FromV1VersionResponse converts a v1 VersionResponse to its internal mirror.
+func FromV1VersionResponse(in *runtimeapi.VersionResponse) *VersionResponse {
+	if in == nil {
+		return nil
+	}
+	return &VersionResponse{
+		Version:           in.Version,
+		RuntimeName:       in.RuntimeName,
+		RuntimeVersion:    in.RuntimeVersion,
+		RuntimeAPIVersion: in.RuntimeApiVersion,
+	}
+}
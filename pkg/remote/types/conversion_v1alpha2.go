@@ -0,0 +1,422 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+)
+
+// FromV1alpha2PodSandboxStatus converts a v1alpha2 PodSandboxStatus to its internal mirror.
+func FromV1alpha2PodSandboxStatus(in *runtimeapi.PodSandboxStatus) *PodSandboxStatus {
+	if in == nil {
+		return nil
+	}
+	out := &PodSandboxStatus{
+		ID:        in.Id,
+		State:     int32(in.State),
+		CreatedAt: in.CreatedAt,
+		Labels:    in.Labels,
+	}
+	if in.Metadata != nil {
+		out.Metadata = &PodSandboxMetadata{
+			Name:      in.Metadata.Name,
+			Namespace: in.Metadata.Namespace,
+			UID:       in.Metadata.Uid,
+			Attempt:   in.Metadata.Attempt,
+		}
+	}
+	if in.Network != nil {
+		out.Network = &PodSandboxNetworkStatus{IP: in.Network.Ip}
+	}
+	return out
+}
+
+// ToV1alpha2PodSandboxStatus converts the internal PodSandboxStatus mirror to its v1alpha2 wire type.
+func ToV1alpha2PodSandboxStatus(in *PodSandboxStatus) *runtimeapi.PodSandboxStatus {
+	if in == nil {
+		return nil
+	}
+	out := &runtimeapi.PodSandboxStatus{
+		Id:        in.ID,
+		State:     runtimeapi.PodSandboxState(in.State),
+		CreatedAt: in.CreatedAt,
+		Labels:    in.Labels,
+	}
+	if in.Metadata != nil {
+		out.Metadata = &runtimeapi.PodSandboxMetadata{
+			Name:      in.Metadata.Name,
+			Namespace: in.Metadata.Namespace,
+			Uid:       in.Metadata.UID,
+			Attempt:   in.Metadata.Attempt,
+		}
+	}
+	if in.Network != nil {
+		out.Network = &runtimeapi.PodSandboxNetworkStatus{Ip: in.Network.IP}
+	}
+	return out
+}
+
+// FromV1alpha2PodSandbox converts a v1alpha2 PodSandbox to its internal mirror.
+func FromV1alpha2PodSandbox(in *runtimeapi.PodSandbox) *PodSandbox {
+	if in == nil {
+		return nil
+	}
+	out := &PodSandbox{
+		ID:          in.Id,
+		State:       int32(in.State),
+		CreatedAt:   in.CreatedAt,
+		Labels:      in.Labels,
+		Annotations: in.Annotations,
+	}
+	if in.Metadata != nil {
+		out.Metadata = &PodSandboxMetadata{
+			Name:      in.Metadata.Name,
+			Namespace: in.Metadata.Namespace,
+			UID:       in.Metadata.Uid,
+			Attempt:   in.Metadata.Attempt,
+		}
+	}
+	return out
+}
+
+// ToV1alpha2PodSandbox converts the internal PodSandbox mirror to its v1alpha2 wire type.
+func ToV1alpha2PodSandbox(in *PodSandbox) *runtimeapi.PodSandbox {
+	if in == nil {
+		return nil
+	}
+	out := &runtimeapi.PodSandbox{
+		Id:          in.ID,
+		State:       runtimeapi.PodSandboxState(in.State),
+		CreatedAt:   in.CreatedAt,
+		Labels:      in.Labels,
+		Annotations: in.Annotations,
+	}
+	if in.Metadata != nil {
+		out.Metadata = &runtimeapi.PodSandboxMetadata{
+			Name:      in.Metadata.Name,
+			Namespace: in.Metadata.Namespace,
+			Uid:       in.Metadata.UID,
+			Attempt:   in.Metadata.Attempt,
+		}
+	}
+	return out
+}
+
+// ToV1alpha2PodSandboxFilter converts the internal PodSandboxFilter mirror to its v1alpha2 wire type.
+func ToV1alpha2PodSandboxFilter(in *PodSandboxFilter) *runtimeapi.PodSandboxFilter {
+	if in == nil {
+		return nil
+	}
+	out := &runtimeapi.PodSandboxFilter{
+		Id:            in.ID,
+		LabelSelector: in.LabelSelector,
+	}
+	if in.State != nil {
+		out.State = &runtimeapi.PodSandboxStateValue{State: runtimeapi.PodSandboxState(*in.State)}
+	}
+	return out
+}
+
+// ToV1alpha2LinuxContainerResources converts the internal LinuxContainerResources mirror to its v1alpha2 wire type.
+func ToV1alpha2LinuxContainerResources(in *LinuxContainerResources) *runtimeapi.LinuxContainerResources {
+	if in == nil {
+		return nil
+	}
+	return &runtimeapi.LinuxContainerResources{
+		CpuPeriod:          in.CPUPeriod,
+		CpuQuota:           in.CPUQuota,
+		CpuShares:          in.CPUShares,
+		MemoryLimitInBytes: in.MemoryLimitInBytes,
+		OomScoreAdj:        in.OomScoreAdj,
+		CpusetCpus:         in.CpusetCpus,
+		CpusetMems:         in.CpusetMems,
+	}
+}
+
+// FromV1alpha2LinuxContainerResources converts a v1alpha2 LinuxContainerResources to its internal mirror.
+func FromV1alpha2LinuxContainerResources(in *runtimeapi.LinuxContainerResources) *LinuxContainerResources {
+	if in == nil {
+		return nil
+	}
+	return &LinuxContainerResources{
+		CPUPeriod:          in.CpuPeriod,
+		CPUQuota:           in.CpuQuota,
+		CPUShares:          in.CpuShares,
+		MemoryLimitInBytes: in.MemoryLimitInBytes,
+		OomScoreAdj:        in.OomScoreAdj,
+		CpusetCpus:         in.CpusetCpus,
+		CpusetMems:         in.CpusetMems,
+	}
+}
+
+// ToV1alpha2ContainerStatus converts the internal ContainerStatus mirror to its v1alpha2 wire type.
+func ToV1alpha2ContainerStatus(in *ContainerStatus) *runtimeapi.ContainerStatus {
+	if in == nil {
+		return nil
+	}
+	out := &runtimeapi.ContainerStatus{
+		Id:          in.ID,
+		State:       runtimeapi.ContainerState(in.State),
+		CreatedAt:   in.CreatedAt,
+		StartedAt:   in.StartedAt,
+		FinishedAt:  in.FinishedAt,
+		ExitCode:    in.ExitCode,
+		Reason:      in.Reason,
+		Message:     in.Message,
+		Labels:      in.Labels,
+		Annotations: in.Annotations,
+	}
+	if in.Metadata != nil {
+		out.Metadata = &runtimeapi.ContainerMetadata{Name: in.Metadata.Name, Attempt: in.Metadata.Attempt}
+	}
+	if in.Image != nil {
+		out.Image = &runtimeapi.ImageSpec{Image: in.Image.Image}
+	}
+	return out
+}
+
+// FromV1alpha2ContainerStatus converts a v1alpha2 ContainerStatus to its internal mirror.
+func FromV1alpha2ContainerStatus(in *runtimeapi.ContainerStatus) *ContainerStatus {
+	if in == nil {
+		return nil
+	}
+	out := &ContainerStatus{
+		ID:          in.Id,
+		State:       int32(in.State),
+		CreatedAt:   in.CreatedAt,
+		StartedAt:   in.StartedAt,
+		FinishedAt:  in.FinishedAt,
+		ExitCode:    in.ExitCode,
+		Reason:      in.Reason,
+		Message:     in.Message,
+		Labels:      in.Labels,
+		Annotations: in.Annotations,
+	}
+	if in.Metadata != nil {
+		out.Metadata = &ContainerMetadata{Name: in.Metadata.Name, Attempt: in.Metadata.Attempt}
+	}
+	if in.Image != nil {
+		out.Image = &ImageSpec{Image: in.Image.Image}
+	}
+	return out
+}
+
+// FromV1alpha2Container converts a v1alpha2 Container to its internal mirror.
+func FromV1alpha2Container(in *runtimeapi.Container) *Container {
+	if in == nil {
+		return nil
+	}
+	out := &Container{
+		ID:           in.Id,
+		PodSandboxID: in.PodSandboxId,
+		State:        int32(in.State),
+		CreatedAt:    in.CreatedAt,
+		Labels:       in.Labels,
+		Annotations:  in.Annotations,
+	}
+	if in.Metadata != nil {
+		out.Metadata = &ContainerMetadata{Name: in.Metadata.Name, Attempt: in.Metadata.Attempt}
+	}
+	if in.Image != nil {
+		out.Image = &ImageSpec{Image: in.Image.Image}
+	}
+	return out
+}
+
+// ToV1alpha2Container converts the internal Container mirror to its v1alpha2 wire type.
+func ToV1alpha2Container(in *Container) *runtimeapi.Container {
+	if in == nil {
+		return nil
+	}
+	out := &runtimeapi.Container{
+		Id:           in.ID,
+		PodSandboxId: in.PodSandboxID,
+		State:        runtimeapi.ContainerState(in.State),
+		CreatedAt:    in.CreatedAt,
+		Labels:       in.Labels,
+		Annotations:  in.Annotations,
+	}
+	if in.Metadata != nil {
+		out.Metadata = &runtimeapi.ContainerMetadata{Name: in.Metadata.Name, Attempt: in.Metadata.Attempt}
+	}
+	if in.Image != nil {
+		out.Image = &runtimeapi.ImageSpec{Image: in.Image.Image}
+	}
+	return out
+}
+
+// ToV1alpha2ContainerFilter converts the internal ContainerFilter mirror to its v1alpha2 wire type.
+func ToV1alpha2ContainerFilter(in *ContainerFilter) *runtimeapi.ContainerFilter {
+	if in == nil {
+		return nil
+	}
+	out := &runtimeapi.ContainerFilter{
+		Id:            in.ID,
+		PodSandboxId:  in.PodSandboxID,
+		LabelSelector: in.LabelSelector,
+	}
+	if in.State != nil {
+		out.State = &runtimeapi.ContainerStateValue{State: runtimeapi.ContainerState(*in.State)}
+	}
+	return out
+}
+
+// ToV1alpha2ContainerStatsFilter converts the internal ContainerStatsFilter mirror to its v1alpha2 wire type.
+func ToV1alpha2ContainerStatsFilter(in *ContainerStatsFilter) *runtimeapi.ContainerStatsFilter {
+	if in == nil {
+		return nil
+	}
+	return &runtimeapi.ContainerStatsFilter{
+		Id:            in.ID,
+		PodSandboxId:  in.PodSandboxID,
+		LabelSelector: in.LabelSelector,
+	}
+}
+
+// FromV1alpha2ContainerStatsFilter converts a v1alpha2 ContainerStatsFilter to its internal mirror.
+func FromV1alpha2ContainerStatsFilter(in *runtimeapi.ContainerStatsFilter) *ContainerStatsFilter {
+	if in == nil {
+		return nil
+	}
+	return &ContainerStatsFilter{
+		ID:            in.Id,
+		PodSandboxID:  in.PodSandboxId,
+		LabelSelector: in.LabelSelector,
+	}
+}
+
+// ToV1alpha2ContainerStats converts the internal ContainerStats mirror to its v1alpha2 wire type.
+func ToV1alpha2ContainerStats(in *ContainerStats) *runtimeapi.ContainerStats {
+	if in == nil {
+		return nil
+	}
+	out := &runtimeapi.ContainerStats{}
+	if in.Attributes != nil {
+		out.Attributes = &runtimeapi.ContainerAttributes{Id: in.Attributes.ID}
+	}
+	if in.CPU != nil {
+		out.Cpu = &runtimeapi.CpuUsage{
+			Timestamp:            in.CPU.Timestamp,
+			UsageCoreNanoSeconds: &runtimeapi.UInt64Value{Value: in.CPU.UsageCoreNanoSeconds},
+		}
+	}
+	if in.Memory != nil {
+		out.Memory = &runtimeapi.MemoryUsage{
+			Timestamp:       in.Memory.Timestamp,
+			WorkingSetBytes: &runtimeapi.UInt64Value{Value: in.Memory.WorkingSetBytes},
+		}
+	}
+	if in.WritableLayer != nil {
+		out.WritableLayer = &runtimeapi.FilesystemUsage{
+			Timestamp: in.WritableLayer.Timestamp,
+			UsedBytes: &runtimeapi.UInt64Value{Value: in.WritableLayer.UsedBytes},
+		}
+	}
+	return out
+}
+
+// FromV1alpha2ContainerStats converts a v1alpha2 ContainerStats to its internal mirror.
+func FromV1alpha2ContainerStats(in *runtimeapi.ContainerStats) *ContainerStats {
+	if in == nil {
+		return nil
+	}
+	out := &ContainerStats{}
+	if in.Attributes != nil {
+		out.Attributes = &ContainerAttributes{ID: in.Attributes.Id}
+	}
+	if in.Cpu != nil {
+		out.CPU = &CPUUsage{Timestamp: in.Cpu.Timestamp, UsageCoreNanoSeconds: in.Cpu.UsageCoreNanoSeconds.GetValue()}
+	}
+	if in.Memory != nil {
+		out.Memory = &MemoryUsage{Timestamp: in.Memory.Timestamp, WorkingSetBytes: in.Memory.WorkingSetBytes.GetValue()}
+	}
+	if in.WritableLayer != nil {
+		out.WritableLayer = &FilesystemUsage{Timestamp: in.WritableLayer.Timestamp, UsedBytes: in.WritableLayer.UsedBytes.GetValue()}
+	}
+	return out
+}
+
+// FromV1alpha2RuntimeStatus converts a v1alpha2 RuntimeStatus to its internal mirror.
+func FromV1alpha2RuntimeStatus(in *runtimeapi.RuntimeStatus) *RuntimeStatus {
+	if in == nil {
+		return nil
+	}
+	out := &RuntimeStatus{}
+	for _, c := range in.Conditions {
+		out.Conditions = append(out.Conditions, &RuntimeCondition{
+			Type:    c.Type,
+			Status:  c.Status,
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+	}
+	return out
+}
+
+// ToV1alpha2RuntimeStatus converts the internal RuntimeStatus mirror to its v1alpha2 wire type.
+func ToV1alpha2RuntimeStatus(in *RuntimeStatus) *runtimeapi.RuntimeStatus {
+	if in == nil {
+		return nil
+	}
+	out := &runtimeapi.RuntimeStatus{}
+	for _, c := range in.Conditions {
+		out.Conditions = append(out.Conditions, &runtimeapi.RuntimeCondition{
+			Type:    c.Type,
+			Status:  c.Status,
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+	}
+	return out
+}
+
+// ToV1alpha2RuntimeConfig converts the internal RuntimeConfig mirror to its v1alpha2 wire type.
+func ToV1alpha2RuntimeConfig(in *RuntimeConfig) *runtimeapi.RuntimeConfig {
+	if in == nil {
+		return nil
+	}
+	out := &runtimeapi.RuntimeConfig{}
+	if in.NetworkConfig != nil {
+		out.NetworkConfig = &runtimeapi.NetworkConfig{PodCidr: in.NetworkConfig.PodCIDR}
+	}
+	return out
+}
+
+// FromV1alpha2RuntimeConfig converts a v1alpha2 RuntimeConfig to its internal mirror.
+func FromV1alpha2RuntimeConfig(in *runtimeapi.RuntimeConfig) *RuntimeConfig {
+	if in == nil {
+		return nil
+	}
+	out := &RuntimeConfig{}
+	if in.NetworkConfig != nil {
+		out.NetworkConfig = &NetworkConfig{PodCIDR: in.NetworkConfig.PodCidr}
+	}
+	return out
+}
+
+// FromV1alpha2VersionResponse converts a v1alpha2 VersionResponse to its internal mirror.
+func FromV1alpha2VersionResponse(in *runtimeapi.VersionResponse) *VersionResponse {
+	if in == nil {
+		return nil
+	}
+	return &VersionResponse{
+		Version:           in.Version,
+		RuntimeName:       in.RuntimeName,
+		RuntimeVersion:    in.RuntimeVersion,
+		RuntimeAPIVersion: in.RuntimeApiVersion,
+	}
+}
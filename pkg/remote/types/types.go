@@ -0,0 +1,199 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package types holds a CRI-version-agnostic mirror of the subset of CRI
+// messages used by RemoteRuntimeService. RemoteRuntimeService negotiates
+// between the CRI v1 and v1alpha2 API versions at connect time; the
+// conversion_v1.go and conversion_v1alpha2.go files in this package
+// translate between these internal types and each wire version, so callers
+// only need to reason about one shape regardless of which API version the
+// runtime actually speaks.
+//
+// PodSandboxConfig and ContainerConfig are deliberately not mirrored here:
+// callers never inspect their fields, only forward them to the runtime
+// as-is, and those two messages carry far too much runtime-relevant state
+// (DNS config, port mappings, mounts, devices, Linux/Windows security
+// context, ...) to safely hand-maintain a field list that callers don't
+// exercise. ConvertPodSandboxConfigToV1 and ConvertContainerConfigToV1 in
+// conversion_v1.go convert those two directly between wire versions instead.
+package types
+
+// PodSandboxMetadata identifies a pod sandbox.
+type PodSandboxMetadata struct {
+	Name      string
+	Namespace string
+	UID       string
+	Attempt   uint32
+}
+
+// PodSandboxNetworkStatus is the internal mirror of CRI's PodSandboxNetworkStatus.
+type PodSandboxNetworkStatus struct {
+	IP string
+}
+
+// PodSandboxStatus is the internal mirror of CRI's PodSandboxStatus.
+type PodSandboxStatus struct {
+	ID        string
+	Metadata  *PodSandboxMetadata
+	State     int32
+	CreatedAt int64
+	Network   *PodSandboxNetworkStatus
+	Labels    map[string]string
+}
+
+// PodSandbox is the internal mirror of CRI's PodSandbox.
+type PodSandbox struct {
+	ID          string
+	Metadata    *PodSandboxMetadata
+	State       int32
+	CreatedAt   int64
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// PodSandboxFilter is the internal mirror of CRI's PodSandboxFilter.
+type PodSandboxFilter struct {
+	ID            string
+	State         *int32
+	LabelSelector map[string]string
+}
+
+// ContainerMetadata identifies a container within a pod sandbox.
+type ContainerMetadata struct {
+	Name    string
+	Attempt uint32
+}
+
+// ImageSpec is the internal mirror of CRI's ImageSpec.
+type ImageSpec struct {
+	Image string
+}
+
+// LinuxContainerResources is the internal mirror of CRI's LinuxContainerResources.
+type LinuxContainerResources struct {
+	CPUPeriod          int64
+	CPUQuota           int64
+	CPUShares          int64
+	MemoryLimitInBytes int64
+	OomScoreAdj        int64
+	CpusetCpus         string
+	CpusetMems         string
+}
+
+// ContainerStatus is the internal mirror of CRI's ContainerStatus.
+type ContainerStatus struct {
+	ID          string
+	Metadata    *ContainerMetadata
+	State       int32
+	CreatedAt   int64
+	StartedAt   int64
+	FinishedAt  int64
+	ExitCode    int32
+	Image       *ImageSpec
+	Reason      string
+	Message     string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// Container is the internal mirror of CRI's Container.
+type Container struct {
+	ID           string
+	PodSandboxID string
+	Metadata     *ContainerMetadata
+	Image        *ImageSpec
+	State        int32
+	CreatedAt    int64
+	Labels       map[string]string
+	Annotations  map[string]string
+}
+
+// ContainerFilter is the internal mirror of CRI's ContainerFilter.
+type ContainerFilter struct {
+	ID            string
+	State         *int32
+	PodSandboxID  string
+	LabelSelector map[string]string
+}
+
+// ContainerAttributes is the internal mirror of CRI's ContainerAttributes.
+type ContainerAttributes struct {
+	ID string
+}
+
+// CPUUsage is the internal mirror of CRI's CpuUsage.
+type CPUUsage struct {
+	Timestamp            int64
+	UsageCoreNanoSeconds uint64
+}
+
+// MemoryUsage is the internal mirror of CRI's MemoryUsage.
+type MemoryUsage struct {
+	Timestamp       int64
+	WorkingSetBytes uint64
+}
+
+// FilesystemUsage is the internal mirror of CRI's FilesystemUsage.
+type FilesystemUsage struct {
+	Timestamp int64
+	UsedBytes uint64
+}
+
+// ContainerStats is the internal mirror of CRI's ContainerStats.
+type ContainerStats struct {
+	Attributes    *ContainerAttributes
+	CPU           *CPUUsage
+	Memory        *MemoryUsage
+	WritableLayer *FilesystemUsage
+}
+
+// ContainerStatsFilter is the internal mirror of CRI's ContainerStatsFilter.
+type ContainerStatsFilter struct {
+	ID            string
+	PodSandboxID  string
+	LabelSelector map[string]string
+}
+
+// RuntimeCondition is the internal mirror of CRI's RuntimeCondition.
+type RuntimeCondition struct {
+	Type    string
+	Status  bool
+	Reason  string
+	Message string
+}
+
+// RuntimeStatus is the internal mirror of CRI's RuntimeStatus.
+type RuntimeStatus struct {
+	Conditions []*RuntimeCondition
+}
+
+// NetworkConfig is the internal mirror of CRI's NetworkConfig.
+type NetworkConfig struct {
+	PodCIDR string
+}
+
+// RuntimeConfig is the internal mirror of CRI's RuntimeConfig.
+type RuntimeConfig struct {
+	NetworkConfig *NetworkConfig
+}
+
+// VersionResponse is the internal mirror of CRI's VersionResponse.
+type VersionResponse struct {
+	Version           string
+	RuntimeName       string
+	RuntimeVersion    string
+	RuntimeAPIVersion string
+}